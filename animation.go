@@ -0,0 +1,476 @@
+package goodp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AnimationClass indica si la animación muestra, resalta u oculta el elemento
+type AnimationClass string
+
+const (
+	AnimationEntrance AnimationClass = "entrance"
+	AnimationEmphasis AnimationClass = "emphasis"
+	AnimationExit     AnimationClass = "exit"
+)
+
+// AnimationEffect es el efecto de una animación. Appear, Fade, Fly y Zoom sirven para
+// entradas y salidas; Grow y Spin son efectos de énfasis.
+type AnimationEffect string
+
+const (
+	EffectAppear AnimationEffect = "appear"
+	EffectFade   AnimationEffect = "fade"
+	EffectFly    AnimationEffect = "fly"
+	EffectZoom   AnimationEffect = "zoom"
+	EffectGrow   AnimationEffect = "grow"
+	EffectSpin   AnimationEffect = "spin"
+)
+
+// AnimationTrigger indica cuándo empieza una animación
+type AnimationTrigger string
+
+const (
+	TriggerOnClick       AnimationTrigger = "on-click"
+	TriggerWithPrevious  AnimationTrigger = "with-previous"
+	TriggerAfterPrevious AnimationTrigger = "after-previous"
+)
+
+// Animation es la animación de un elemento de la diapositiva
+type Animation struct {
+	Class     AnimationClass
+	Effect    AnimationEffect
+	Trigger   AnimationTrigger    // Por defecto TriggerOnClick
+	Delay     float64             // Retraso en segundos desde el inicio del paso
+	Duration  float64             // Duración en segundos (por defecto 0.5)
+	Direction TransitionDirection // Dirección de Fly (por defecto FromBottom)
+	Target    int                 // Z-index del elemento animado (lo asigna AddAnimation)
+	// ByParagraph anima los párrafos del elemento uno a uno, cada párrafo de primer
+	// nivel con un clic (solo cuadros de texto y listas)
+	ByParagraph bool
+	// ParagraphInterval, con ByParagraph, muestra todos los párrafos con un solo inicio,
+	// uno tras otro con esta separación en segundos, en lugar de un clic por párrafo
+	ParagraphInterval float64
+	paragraph         string // Párrafo animado dentro del elemento (lo asigna expandAnimations)
+}
+
+// defaultAnimationDuration es la duración por defecto de las animaciones, en segundos
+const defaultAnimationDuration = 0.5
+
+// Efectos válidos para cada tipo de animación
+var animationEffects = map[AnimationClass][]AnimationEffect{
+	AnimationEntrance: {EffectAppear, EffectFade, EffectFly, EffectZoom},
+	AnimationExit:     {EffectAppear, EffectFade, EffectFly, EffectZoom},
+	AnimationEmphasis: {EffectGrow, EffectSpin},
+}
+
+// AddAnimation anima el elemento de la diapositiva con el Z-index indicado (el campo
+// ZIndex del elemento devuelto por AddTextBox, AddImage..., o Slide.LastZIndex).
+// Las animaciones se reproducen en el orden en que se añaden.
+func (g *ODPGenerator) AddAnimation(slide *Slide, zIndex int, animation Animation) error {
+	if g.slideIndex(slide) == -1 {
+		return fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	if !slide.hasElement(zIndex) {
+		return fmt.Errorf("la diapositiva no tiene ningún elemento con Z-index %d", zIndex)
+	}
+
+	if _, ok := slide.animationParagraphs(zIndex); animation.ByParagraph && !ok {
+		return fmt.Errorf("solo se pueden animar por párrafos los cuadros de texto y las listas")
+	}
+
+	prepared, err := prepareAnimation(animation)
+	if err != nil {
+		return err
+	}
+	prepared.Target = zIndex
+	slide.Animations = append(slide.Animations, prepared)
+	return nil
+}
+
+// LastZIndex devuelve el Z-index del último elemento añadido a la diapositiva
+func (s *Slide) LastZIndex() int {
+	return s.lastZIndex
+}
+
+// prepareAnimation valida la animación y completa los valores por defecto
+func prepareAnimation(animation Animation) (Animation, error) {
+	effects, ok := animationEffects[animation.Class]
+	if !ok {
+		return Animation{}, fmt.Errorf("tipo de animación no soportado: %q", animation.Class)
+	}
+	valid := false
+	for _, effect := range effects {
+		valid = valid || effect == animation.Effect
+	}
+	if !valid {
+		return Animation{}, fmt.Errorf("el efecto %q no está disponible para animaciones de tipo %q", animation.Effect, animation.Class)
+	}
+
+	switch animation.Trigger {
+	case "":
+		animation.Trigger = TriggerOnClick
+	case TriggerOnClick, TriggerWithPrevious, TriggerAfterPrevious:
+	default:
+		return Animation{}, fmt.Errorf("inicio de animación no soportado: %q", animation.Trigger)
+	}
+	switch animation.Direction {
+	case "":
+		animation.Direction = FromBottom
+	case FromLeft, FromRight, FromTop, FromBottom:
+	default:
+		return Animation{}, fmt.Errorf("dirección de animación no soportada: %q", animation.Direction)
+	}
+	if animation.Delay < 0 || animation.Duration < 0 || animation.ParagraphInterval < 0 {
+		return Animation{}, fmt.Errorf("el retraso, la duración y la separación entre párrafos de la animación no pueden ser negativos")
+	}
+	if animation.ParagraphInterval > 0 && !animation.ByParagraph {
+		return Animation{}, fmt.Errorf("la separación entre párrafos solo se usa en las animaciones por párrafos")
+	}
+	if animation.Duration == 0 {
+		animation.Duration = defaultAnimationDuration
+	}
+	return animation, nil
+}
+
+// hasElement indica si la diapositiva tiene un elemento con el Z-index indicado
+func (s *Slide) hasElement(zIndex int) bool {
+	for _, element := range s.SortedElements() {
+		if element.ZIndex == zIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// isAnimated indica si el elemento con el Z-index indicado tiene animaciones
+func (s *Slide) isAnimated(zIndex int) bool {
+	for _, animation := range s.Animations {
+		if animation.Target == zIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// isAnimatedByParagraph indica si el elemento con el Z-index indicado se anima por párrafos
+func (s *Slide) isAnimatedByParagraph(zIndex int) bool {
+	for _, animation := range s.Animations {
+		if animation.Target == zIndex && animation.ByParagraph {
+			return true
+		}
+	}
+	return false
+}
+
+// paragraphTarget es un párrafo de un cuadro de texto o una lista que se anima por separado
+type paragraphTarget struct {
+	Path  string // Posición del párrafo ("2" o, en las listas, "0_1" para el segundo hijo del primero)
+	Level int    // Nivel del párrafo en la lista (0 en los cuadros de texto)
+}
+
+// animationParagraphs devuelve los párrafos del cuadro de texto o la lista con el
+// Z-index indicado, y false si el elemento no es ninguno de los dos
+func (s *Slide) animationParagraphs(zIndex int) ([]paragraphTarget, bool) {
+	for _, tb := range s.TextBoxes {
+		if tb.ZIndex != zIndex {
+			continue
+		}
+		count := len(tb.Paragraphs)
+		if count == 0 {
+			count = len(contentLines(tb.Content))
+		}
+		targets := make([]paragraphTarget, count)
+		for i := range targets {
+			targets[i].Path = strconv.Itoa(i)
+		}
+		return targets, true
+	}
+
+	for _, list := range s.Lists {
+		if list.ZIndex != zIndex {
+			continue
+		}
+		var targets []paragraphTarget
+		var walk func(items []ListItem, prefix string, level int)
+		walk = func(items []ListItem, prefix string, level int) {
+			for i, item := range items {
+				path := prefix + strconv.Itoa(i)
+				targets = append(targets, paragraphTarget{Path: path, Level: level})
+				walk(item.Items, path+"_", level+1)
+			}
+		}
+		walk(list.Items, "", 0)
+		return targets, true
+	}
+	return nil, false
+}
+
+// animationLength devuelve los segundos que dura una animación; las animaciones por
+// párrafos con un solo inicio duran hasta que termina el efecto del último párrafo
+func (s *Slide) animationLength(animation Animation) float64 {
+	if !animation.ByParagraph {
+		return animation.Duration
+	}
+	paragraphs, _ := s.animationParagraphs(animation.Target)
+	if len(paragraphs) == 0 {
+		return animation.Duration
+	}
+	return animation.ParagraphInterval*float64(len(paragraphs)-1) + animation.Duration
+}
+
+// contentLines divide el contenido de un cuadro de texto en líneas
+func contentLines(content string) []string {
+	return strings.Split(content, "<text:line-break/>")
+}
+
+// expandAnimations sustituye cada animación por párrafos con un clic por párrafo por
+// una animación para cada párrafo. El primero conserva el inicio de la animación; los
+// siguientes de primer nivel empiezan con un clic y los subelementos, con su elemento
+// padre. Las animaciones con ParagraphInterval se quedan como están, porque se
+// guardan como un único anim:iterate.
+func expandAnimations(slide Slide) []Animation {
+	var animations []Animation
+	for _, animation := range slide.Animations {
+		if !animation.ByParagraph || animation.ParagraphInterval > 0 {
+			animations = append(animations, animation)
+			continue
+		}
+		targets, _ := slide.animationParagraphs(animation.Target)
+		for i, target := range targets {
+			paragraph := animation
+			paragraph.ByParagraph = false
+			paragraph.paragraph = target.Path
+			switch {
+			case i == 0:
+			case target.Level == 0:
+				paragraph.Trigger = TriggerOnClick
+			default:
+				paragraph.Trigger = TriggerWithPrevious
+			}
+			animations = append(animations, paragraph)
+		}
+	}
+	return animations
+}
+
+// animationStep es un paso de la secuencia principal, que empieza con un clic (o
+// automáticamente si es el primero y no espera al clic)
+type animationStep struct {
+	Begin  string // "next" (al hacer clic) o "0s"
+	Groups []animationGroup
+}
+
+// animationGroup son las animaciones de un paso que empiezan a la vez
+type animationGroup struct {
+	Begin      float64 // Segundos desde el inicio del paso
+	Animations []Animation
+}
+
+// animationSteps agrupa las animaciones de una diapositiva en pasos (un clic cada uno)
+// y, dentro de cada paso, en grupos que empiezan a la vez
+func animationSteps(slide Slide) []animationStep {
+	var steps []animationStep
+	for _, animation := range expandAnimations(slide) {
+		if len(steps) == 0 || animation.Trigger == TriggerOnClick {
+			begin := "next"
+			if len(steps) == 0 && animation.Trigger != TriggerOnClick {
+				begin = "0s"
+			}
+			steps = append(steps, animationStep{Begin: begin, Groups: []animationGroup{{Animations: []Animation{animation}}}})
+			continue
+		}
+
+		step := &steps[len(steps)-1]
+		last := &step.Groups[len(step.Groups)-1]
+		if animation.Trigger == TriggerWithPrevious {
+			last.Animations = append(last.Animations, animation)
+			continue
+		}
+
+		// Después de la anterior: empieza cuando termina el grupo anterior
+		end := 0.0
+		for _, previous := range last.Animations {
+			if e := previous.Delay + slide.animationLength(previous); e > end {
+				end = e
+			}
+		}
+		step.Groups = append(step.Groups, animationGroup{Begin: last.Begin + end, Animations: []Animation{animation}})
+	}
+	return steps
+}
+
+// elementID devuelve los atributos de identificador de un elemento si está animado,
+// para que las animaciones puedan referirse a él
+func (g *ODPGenerator) elementID(scope string, zIndex int) string {
+	if slide := g.scopeSlide(scope); slide == nil || !slide.isAnimated(zIndex) {
+		return ""
+	}
+	id := animationElementID(scope, zIndex)
+	return fmt.Sprintf(` xml:id="%s" draw:id="%s"`, id, id)
+}
+
+// animationElementID devuelve el identificador (xml:id) de un elemento animado
+func animationElementID(scope string, zIndex int) string {
+	return fmt.Sprintf("id%s_%d", scope, zIndex)
+}
+
+// animationTargetID devuelve el identificador del elemento o párrafo que anima una animación
+func animationTargetID(scope string, animation Animation) string {
+	if animation.paragraph != "" {
+		return animationParagraphID(scope, animation.Target, animation.paragraph)
+	}
+	return animationElementID(scope, animation.Target)
+}
+
+// animationParagraphID devuelve el identificador (xml:id) de un párrafo animado
+func animationParagraphID(scope string, zIndex int, path string) string {
+	return fmt.Sprintf("%s_p%s", animationElementID(scope, zIndex), path)
+}
+
+// paragraphID devuelve el atributo de identificador de un párrafo si su elemento se
+// anima por párrafos
+func (g *ODPGenerator) paragraphID(scope string, zIndex int, path string) string {
+	if slide := g.scopeSlide(scope); slide == nil || !slide.isAnimatedByParagraph(zIndex) {
+		return ""
+	}
+	return fmt.Sprintf(` xml:id="%s"`, animationParagraphID(scope, zIndex, path))
+}
+
+// contentParagraphs devuelve los párrafos en los que se muestra el contenido de un
+// cuadro de texto: uno solo, o uno por línea si se anima por párrafos
+func (g *ODPGenerator) contentParagraphs(scope string, tb TextBox) []string {
+	if slide := g.scopeSlide(scope); slide == nil || !slide.isAnimatedByParagraph(tb.ZIndex) {
+		return []string{tb.Content}
+	}
+	return contentLines(tb.Content)
+}
+
+// animationPreset devuelve el identificador del efecto predefinido de Impress
+func animationPreset(animation Animation) string {
+	effect := string(animation.Effect)
+	switch {
+	case animation.Class == AnimationExit && animation.Effect == EffectAppear:
+		effect = "disappear"
+	case animation.Effect == EffectFade && animation.Class == AnimationEntrance:
+		effect = "fade-in"
+	case animation.Effect == EffectFade:
+		effect = "fade-out"
+	case animation.Effect == EffectFly && animation.Class == AnimationEntrance:
+		effect = "fly-in"
+	case animation.Effect == EffectFly:
+		effect = "fly-out"
+	case animation.Effect == EffectGrow:
+		effect = "grow-and-shrink"
+	}
+	return fmt.Sprintf("ooo-%s-%s", animation.Class, effect)
+}
+
+// flyValues devuelve los valores de las animaciones de posición (x e y) del efecto Fly
+func flyValues(animation Animation) map[string]string {
+	x, y := "x;x", "y;y"
+	switch animation.Direction {
+	case FromLeft:
+		x = "0-width/2;x"
+	case FromRight:
+		x = "1+width/2;x"
+	case FromTop:
+		y = "0-height/2;y"
+	default:
+		y = "1+height/2;y"
+	}
+	if animation.Class == AnimationExit {
+		x, y = reverseValues(x), reverseValues(y)
+	}
+	return map[string]string{"X": x, "Y": y}
+}
+
+// reverseValues invierte el orden de una lista de valores SMIL ("a;b" -> "b;a")
+func reverseValues(values string) string {
+	parts := strings.Split(values, ";")
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, ";")
+}
+
+// targetElement devuelve el atributo con el elemento al que se aplica un paso de una
+// animación. Los pasos de las animaciones por párrafos no lo llevan: lo indica el
+// anim:iterate que los contiene.
+func targetElement(target string) string {
+	if target == "" {
+		return ""
+	}
+	return fmt.Sprintf(` smil:targetElement="%s"`, target)
+}
+
+// formatSeconds da formato a una duración SMIL en segundos
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%gs", seconds)
+}
+
+// animationTemplates contiene la plantilla del árbol de animaciones de una diapositiva
+const animationTemplates = `
+{{define "animations"}}
+    {{$scope := .Scope}}
+    <anim:par presentation:node-type="timing-root">
+        <anim:seq presentation:node-type="main-sequence">
+            {{range animationSteps .Data}}
+            <anim:par smil:begin="{{.Begin}}">
+                {{range .Groups}}
+                <anim:par smil:begin="{{seconds .Begin}}">
+                    {{range .Animations}}
+                    {{template "animationEffect" (scoped (animationTargetID $scope .) .)}}
+                    {{end}}
+                </anim:par>
+                {{end}}
+            </anim:par>
+            {{end}}
+        </anim:seq>
+    </anim:par>
+{{end}}
+
+{{define "animationEffect"}}
+    {{$target := .Scope}}
+    {{with .Data}}
+    {{if .ByParagraph}}
+    <anim:iterate smil:begin="{{seconds .Delay}}" smil:fill="hold" smil:targetElement="{{$target}}" anim:sub-item="text" anim:iterate-type="by-paragraph" anim:iterate-interval="{{seconds .ParagraphInterval}}" {{template "animationPreset" .}}>
+        {{template "animationNodes" (scoped "" .)}}
+    </anim:iterate>
+    {{else}}
+    <anim:par smil:begin="{{seconds .Delay}}" smil:fill="hold" {{template "animationPreset" .}}>
+        {{template "animationNodes" (scoped $target .)}}
+    </anim:par>
+    {{end}}
+    {{end}}
+{{end}}
+
+{{define "animationPreset"}}presentation:node-type="{{.Trigger}}" presentation:preset-class="{{.Class}}" presentation:preset-id="{{animationPreset .}}"{{if eq .Effect "fly"}} presentation:preset-sub-type="from-{{.Direction}}"{{end}}{{end}}
+
+{{define "animationNodes"}}
+    {{$target := .Scope}}
+    {{with .Data}}
+        {{if eq .Class "entrance"}}
+        <anim:set smil:begin="0s" smil:dur="0.001s" smil:fill="hold"{{targetElement $target}} smil:attributeName="visibility" smil:to="visible"/>
+        {{end}}
+        {{if eq .Effect "fade"}}
+        <anim:transitionFilter smil:dur="{{seconds .Duration}}"{{targetElement $target}} smil:type="fade" smil:subtype="crossfade"{{if eq .Class "exit"}} smil:mode="out"{{end}}/>
+        {{else if eq .Effect "fly"}}
+        {{$values := flyValues .}}
+        <anim:animate smil:dur="{{seconds .Duration}}" smil:fill="hold"{{targetElement $target}} smil:attributeName="x" smil:values="{{$values.X}}" smil:keyTimes="0;1" presentation:additive="base"/>
+        <anim:animate smil:dur="{{seconds .Duration}}" smil:fill="hold"{{targetElement $target}} smil:attributeName="y" smil:values="{{$values.Y}}" smil:keyTimes="0;1" presentation:additive="base"/>
+        {{else if eq .Effect "zoom"}}
+        <anim:animate smil:dur="{{seconds .Duration}}" smil:fill="hold"{{targetElement $target}} smil:attributeName="width" smil:values="{{if eq .Class "exit"}}width;0{{else}}0;width{{end}}" smil:keyTimes="0;1" presentation:additive="base"/>
+        <anim:animate smil:dur="{{seconds .Duration}}" smil:fill="hold"{{targetElement $target}} smil:attributeName="height" smil:values="{{if eq .Class "exit"}}height;0{{else}}0;height{{end}}" smil:keyTimes="0;1" presentation:additive="base"/>
+        {{else if eq .Effect "grow"}}
+        <anim:animateTransform smil:dur="{{seconds .Duration}}" smil:fill="hold"{{targetElement $target}} smil:by="1.5,1.5" smil:autoReverse="true" presentation:additive="base" svg:type="scale"/>
+        {{else if eq .Effect "spin"}}
+        <anim:animateTransform smil:dur="{{seconds .Duration}}" smil:fill="hold"{{targetElement $target}} smil:by="360" presentation:additive="base" svg:type="rotate"/>
+        {{end}}
+        {{if eq .Class "exit"}}
+        <anim:set smil:begin="{{if eq .Effect "appear"}}0s{{else}}{{seconds .Duration}}{{end}}" smil:dur="0.001s" smil:fill="hold"{{targetElement $target}} smil:attributeName="visibility" smil:to="hidden"/>
+        {{end}}
+    {{end}}
+{{end}}
+`
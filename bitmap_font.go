@@ -0,0 +1,157 @@
+package goodp
+
+import "strings"
+
+// glyphRows son las filas de la fuente de mapa de bits con la que se dibuja el texto
+// al generar imágenes: 5 columnas por 7 filas sobre la línea base y una fila más
+// para los trazos descendentes ("#" es un píxel encendido).
+var glyphRows = map[rune]string{
+	'!':  "..#.. ..#.. ..#.. ..#.. ..... ..... ..#..",
+	'"':  ".#.#. .#.#. .#.#. ..... ..... ..... .....",
+	'#':  ".#.#. .#.#. ##### .#.#. ##### .#.#. .#.#.",
+	'$':  "..#.. .#### #.#.. .###. ..#.# ####. ..#..",
+	'%':  "##... ##..# ...#. ..#.. .#... #..## ...##",
+	'&':  ".##.. #..#. #.#.. .#... #.#.# #..#. .##.#",
+	'\'': ".##.. ..#.. .#... ..... ..... ..... .....",
+	'(':  "...#. ..#.. .#... .#... .#... ..#.. ...#.",
+	')':  ".#... ..#.. ...#. ...#. ...#. ..#.. .#...",
+	'*':  "..... ..#.. #.#.# .###. #.#.# ..#.. .....",
+	'+':  "..... ..#.. ..#.. ##### ..#.. ..#.. .....",
+	',':  "..... ..... ..... ..... .##.. ..#.. .#...",
+	'-':  "..... ..... ..... ##### ..... ..... .....",
+	'.':  "..... ..... ..... ..... ..... .##.. .##..",
+	'/':  "..... ....# ...#. ..#.. .#... #.... .....",
+	'0':  ".###. #...# #..## #.#.# ##..# #...# .###.",
+	'1':  "..#.. .##.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'2':  ".###. #...# ....# ...#. ..#.. .#... #####",
+	'3':  "##### ...#. ..#.. ...#. ....# #...# .###.",
+	'4':  "...#. ..##. .#.#. #..#. ##### ...#. ...#.",
+	'5':  "##### #.... ####. ....# ....# #...# .###.",
+	'6':  "..##. .#... #.... ####. #...# #...# .###.",
+	'7':  "##### ....# ...#. ..#.. .#... .#... .#...",
+	'8':  ".###. #...# #...# .###. #...# #...# .###.",
+	'9':  ".###. #...# #...# .#### ....# ...#. .##..",
+	':':  "..... .##.. .##.. ..... .##.. .##.. .....",
+	';':  "..... .##.. .##.. ..... .##.. ..#.. .#...",
+	'<':  "...#. ..#.. .#... #.... .#... ..#.. ...#.",
+	'=':  "..... ..... ##### ..... ##### ..... .....",
+	'>':  ".#... ..#.. ...#. ....# ...#. ..#.. .#...",
+	'?':  ".###. #...# ....# ...#. ..#.. ..... ..#..",
+	'@':  ".###. #...# ....# .##.# #.#.# #.#.# .###.",
+	'A':  ".###. #...# #...# #...# ##### #...# #...#",
+	'B':  "####. #...# #...# ####. #...# #...# ####.",
+	'C':  ".###. #...# #.... #.... #.... #...# .###.",
+	'D':  "###.. #..#. #...# #...# #...# #..#. ###..",
+	'E':  "##### #.... #.... ####. #.... #.... #####",
+	'F':  "##### #.... #.... ####. #.... #.... #....",
+	'G':  ".###. #...# #.... #.### #...# #...# .####",
+	'H':  "#...# #...# #...# ##### #...# #...# #...#",
+	'I':  ".###. ..#.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'J':  "..### ...#. ...#. ...#. ...#. #..#. .##..",
+	'K':  "#...# #..#. #.#.. ##... #.#.. #..#. #...#",
+	'L':  "#.... #.... #.... #.... #.... #.... #####",
+	'M':  "#...# ##.## #.#.# #.#.# #...# #...# #...#",
+	'N':  "#...# #...# ##..# #.#.# #..## #...# #...#",
+	'O':  ".###. #...# #...# #...# #...# #...# .###.",
+	'P':  "####. #...# #...# ####. #.... #.... #....",
+	'Q':  ".###. #...# #...# #...# #.#.# #..#. .##.#",
+	'R':  "####. #...# #...# ####. #.#.. #..#. #...#",
+	'S':  ".#### #.... #.... .###. ....# ....# ####.",
+	'T':  "##### ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'U':  "#...# #...# #...# #...# #...# #...# .###.",
+	'V':  "#...# #...# #...# #...# #...# .#.#. ..#..",
+	'W':  "#...# #...# #...# #.#.# #.#.# #.#.# .#.#.",
+	'X':  "#...# #...# .#.#. ..#.. .#.#. #...# #...#",
+	'Y':  "#...# #...# #...# .#.#. ..#.. ..#.. ..#..",
+	'Z':  "##### ....# ...#. ..#.. .#... #.... #####",
+	'[':  ".###. .#... .#... .#... .#... .#... .###.",
+	'\\': "..... #.... .#... ..#.. ...#. ....# .....",
+	']':  ".###. ...#. ...#. ...#. ...#. ...#. .###.",
+	'^':  "..#.. .#.#. #...# ..... ..... ..... .....",
+	'_':  "..... ..... ..... ..... ..... ..... #####",
+	'`':  ".#... ..#.. ...#. ..... ..... ..... .....",
+	'a':  "..... ..... .###. ....# .#### #...# .####",
+	'b':  "#.... #.... #.##. ##..# #...# #...# ####.",
+	'c':  "..... ..... .###. #.... #.... #...# .###.",
+	'd':  "....# ....# .##.# #..## #...# #...# .####",
+	'e':  "..... ..... .###. #...# ##### #.... .###.",
+	'f':  "..##. .#..# .#... ###.. .#... .#... .#...",
+	'g':  "..... ..... .#### #...# #...# .#### ....# .###.",
+	'h':  "#.... #.... #.##. ##..# #...# #...# #...#",
+	'i':  "..#.. ..... .##.. ..#.. ..#.. ..#.. .###.",
+	'j':  "...#. ..... ..##. ...#. ...#. ...#. #..#. .##..",
+	'k':  "#.... #.... #..#. #.#.. ##... #.#.. #..#.",
+	'l':  ".##.. ..#.. ..#.. ..#.. ..#.. ..#.. .###.",
+	'm':  "..... ..... ##.#. #.#.# #.#.# #...# #...#",
+	'n':  "..... ..... #.##. ##..# #...# #...# #...#",
+	'o':  "..... ..... .###. #...# #...# #...# .###.",
+	'p':  "..... ..... ####. #...# #...# ####. #.... #....",
+	'q':  "..... ..... .#### #...# #...# .#### ....# ....#",
+	'r':  "..... ..... #.##. ##..# #.... #.... #....",
+	's':  "..... ..... .#### #.... .###. ....# ####.",
+	't':  ".#... .#... ###.. .#... .#... .#..# ..##.",
+	'u':  "..... ..... #...# #...# #...# #..## .##.#",
+	'v':  "..... ..... #...# #...# #...# .#.#. ..#..",
+	'w':  "..... ..... #...# #...# #.#.# #.#.# .#.#.",
+	'x':  "..... ..... #...# .#.#. ..#.. .#.#. #...#",
+	'y':  "..... ..... #...# #...# #...# .#### ....# .###.",
+	'z':  "..... ..... ##### ...#. ..#.. .#... #####",
+	'{':  "...#. ..#.. ..#.. .#... ..#.. ..#.. ...#.",
+	'|':  "..#.. ..#.. ..#.. ..#.. ..#.. ..#.. ..#..",
+	'}':  ".#... ..#.. ..#.. ...#. ..#.. ..#.. .#...",
+	'~':  "..... ..... .#... #.#.# ...#. ..... .....",
+	'€':  "..### .#... ####. .#... ####. .#... ..###",
+	'—':  "..... ..... ..... ##### ..... ..... .....",
+	'…':  "..... ..... ..... ..... ..... ..... #.#.#",
+	'•':  "..... ..... .###. .###. .###. ..... .....",
+	'ñ':  ".##.# #..#. ..... #.##. ##..# #...# #...#",
+}
+
+// accentMarks son las dos filas superiores con las que se dibujan las minúsculas
+// acentuadas sobre su letra sin acento
+var accentMarks = map[rune]string{
+	'á': "...#. ..#..", 'é': "...#. ..#..", 'í': "...#. ..#..", 'ó': "...#. ..#..", 'ú': "...#. ..#..",
+	'à': ".#... ..#..", 'è': ".#... ..#..", 'ì': ".#... ..#..", 'ò': ".#... ..#..", 'ù': ".#... ..#..",
+	'ä': ".#.#. .....", 'ë': ".#.#. .....", 'ï': ".#.#. .....", 'ö': ".#.#. .....", 'ü': ".#.#. .....",
+}
+
+// glyphs son los caracteres de glyphRows ya convertidos a máscaras de bits, con
+// el bit 4 como columna izquierda
+var (
+	glyphs  = parseGlyphs(glyphRows)
+	accents = parseGlyphs(accentMarks)
+)
+
+// parseGlyphs convierte las filas de texto de la fuente en máscaras de bits
+func parseGlyphs(rows map[rune]string) map[rune][8]uint8 {
+	parsed := make(map[rune][8]uint8, len(rows))
+	for c, text := range rows {
+		var glyph [8]uint8
+		for i, row := range strings.Fields(text) {
+			for _, pixel := range row {
+				glyph[i] <<= 1
+				if pixel == '#' {
+					glyph[i] |= 1
+				}
+			}
+		}
+		parsed[c] = glyph
+	}
+	return parsed
+}
+
+// glyphFor devuelve el carácter de la fuente de mapa de bits que representa c,
+// usando la letra sin acento cuando la fuente no tiene el carácter
+func glyphFor(c rune) ([8]uint8, bool) {
+	if glyph, ok := glyphs[c]; ok {
+		return glyph, true
+	}
+	for _, base := range accentedLetters.Replace(string(c)) {
+		glyph, ok := glyphs[base]
+		if mark, found := accents[c]; found && ok {
+			glyph[0], glyph[1] = mark[0], mark[1]
+		}
+		return glyph, ok
+	}
+	return [8]uint8{}, false
+}
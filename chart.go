@@ -0,0 +1,326 @@
+package goodp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ChartType indica el tipo de un gráfico
+type ChartType string
+
+const (
+	ChartBar     ChartType = "bar"
+	ChartLine    ChartType = "line"
+	ChartPie     ChartType = "pie"
+	ChartArea    ChartType = "area"
+	ChartScatter ChartType = "scatter"
+)
+
+// mediaTypeChart es el tipo MIME de los objetos de gráfico incrustados
+const mediaTypeChart = "application/vnd.oasis.opendocument.chart"
+
+// ChartSeries es una serie de datos de un gráfico
+type ChartSeries struct {
+	Name   string
+	Values []float64 // Un valor por categoría
+	Color  string    // Color de la serie (#RRGGBB), vacío para el color por defecto
+}
+
+// ChartSpec describe los datos y el aspecto de un gráfico. En los gráficos de
+// dispersión las categorías son los valores del eje X y deben ser números.
+// Los gráficos circulares solo representan la primera serie.
+type ChartSpec struct {
+	Type       ChartType
+	Title      string
+	Categories []string
+	Series     []ChartSeries
+	HideLegend bool
+}
+
+// Chart es un gráfico nativo incrustado en la diapositiva, editable desde Impress
+type Chart struct {
+	Spec       ChartSpec
+	X          string // Posición X en cm
+	Y          string // Posición Y en cm
+	Width      string // Ancho en cm
+	Height     string // Alto en cm
+	ObjectName string // Nombre del subdocumento dentro del paquete ("Object 1")
+	ZIndex     int
+	files      []packageFile // Archivos del subdocumento del gráfico
+}
+
+// Colores por defecto de las series, los mismos que usa LibreOffice
+var chartPalette = []string{
+	"#004586", "#FF420E", "#FFD320", "#579D1C", "#7E0021", "#83CAFF",
+	"#314004", "#AECF00", "#4B1F6F", "#FF950E", "#C5000B", "#0084D1",
+}
+
+// AddChart añade un gráfico nativo a la diapositiva especificada y devuelve un puntero
+// a él. Los datos se guardan en una tabla interna del gráfico, por lo que se pueden
+// editar desde Impress.
+func (g *ODPGenerator) AddChart(slide *Slide, spec ChartSpec, x, y, width, height float64, zIndex ...int) (*Chart, error) {
+	if g.slideIndex(slide) == -1 {
+		return nil, fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("las dimensiones del gráfico deben ser positivas")
+	}
+	if err := validateChartSpec(spec); err != nil {
+		return nil, err
+	}
+
+	chart := &Chart{
+		Spec:       copyChartSpec(spec),
+		X:          fmt.Sprintf("%.2fcm", x),
+		Y:          fmt.Sprintf("%.2fcm", y),
+		Width:      fmt.Sprintf("%.2fcm", width),
+		Height:     fmt.Sprintf("%.2fcm", height),
+		ObjectName: g.nextObjectName(),
+	}
+	content, err := renderChart(*chart)
+	if err != nil {
+		return nil, err
+	}
+	chart.files = []packageFile{
+		{Name: chart.ObjectName + "/", MediaType: mediaTypeChart},
+		{Name: chart.ObjectName + "/content.xml", MediaType: "text/xml", Data: content},
+	}
+
+	chart.ZIndex = slide.getNextZIndex(zIndex...)
+	slide.Charts = append(slide.Charts, chart)
+	return chart, nil
+}
+
+// validateChartSpec comprueba que los datos del gráfico son coherentes
+func validateChartSpec(spec ChartSpec) error {
+	switch spec.Type {
+	case ChartBar, ChartLine, ChartPie, ChartArea, ChartScatter:
+	default:
+		return fmt.Errorf("tipo de gráfico no soportado: %q", spec.Type)
+	}
+	if len(spec.Categories) == 0 {
+		return fmt.Errorf("el gráfico no tiene categorías")
+	}
+	if len(spec.Series) == 0 {
+		return fmt.Errorf("el gráfico no tiene series")
+	}
+	for i, series := range spec.Series {
+		if len(series.Values) != len(spec.Categories) {
+			return fmt.Errorf("la serie %d tiene %d valores para %d categorías", i, len(series.Values), len(spec.Categories))
+		}
+		if series.Color != "" && !isHexColor(series.Color) {
+			return fmt.Errorf("formato de color inválido en la serie %d: debe ser #RRGGBB", i)
+		}
+		for j, value := range series.Values {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return fmt.Errorf("el valor %d de la serie %d no es un número finito: %v", j, i, value)
+			}
+		}
+	}
+	if spec.Type == ChartScatter {
+		for _, category := range spec.Categories {
+			value, err := strconv.ParseFloat(category, 64)
+			if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+				return fmt.Errorf("los gráficos de dispersión necesitan valores numéricos en el eje X: %q", category)
+			}
+		}
+	}
+	return nil
+}
+
+// copyChartSpec copia los datos del gráfico para que los cambios posteriores del
+// llamador no afecten al gráfico
+func copyChartSpec(spec ChartSpec) ChartSpec {
+	spec.Categories = append([]string(nil), spec.Categories...)
+	series := make([]ChartSeries, len(spec.Series))
+	for i, s := range spec.Series {
+		s.Values = append([]float64(nil), s.Values...)
+		series[i] = s
+	}
+	spec.Series = series
+	return spec
+}
+
+// nextObjectName devuelve el primer nombre de subdocumento ("Object N") libre, que no
+// usen ni los gráficos ni ninguna otra carpeta del paquete
+func (g *ODPGenerator) nextObjectName() string {
+	used := make(map[string]bool)
+	for _, file := range g.packageFiles() {
+		used[strings.SplitN(file.Name, "/", 2)[0]] = true
+	}
+	for _, slide := range g.Slides {
+		for _, chart := range slide.Charts {
+			used[chart.ObjectName] = true
+		}
+	}
+	for n := 1; ; n++ {
+		name := fmt.Sprintf("Object %d", n)
+		if !used[name] {
+			return name
+		}
+	}
+}
+
+// chartClass devuelve la clase de gráfico de OpenDocument
+func chartClass(chartType ChartType) string {
+	if chartType == ChartPie {
+		return "chart:circle"
+	}
+	return "chart:" + string(chartType)
+}
+
+// chartColumn devuelve el nombre de la columna index (0 = "A") de la tabla del gráfico
+func chartColumn(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// chartColor devuelve el color de una serie o punto de datos
+func chartColor(custom string, index int) string {
+	if custom != "" {
+		return custom
+	}
+	return chartPalette[index%len(chartPalette)]
+}
+
+// renderChart genera el content.xml del subdocumento de un gráfico
+func renderChart(chart Chart) ([]byte, error) {
+	chartTemplate := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+    xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+    xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+    xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+    xmlns:draw="urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"
+    xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"
+    xmlns:xlink="http://www.w3.org/1999/xlink"
+    xmlns:svg="urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"
+    xmlns:chart="urn:oasis:names:tc:opendocument:xmlns:chart:1.0"
+    office:version="1.2">
+    <office:automatic-styles>
+        <style:style style:name="ch1" style:family="chart">
+            <style:graphic-properties draw:stroke="none" draw:fill="solid" draw:fill-color="#FFFFFF"/>
+        </style:style>
+        {{range $index, $series := .Spec.Series}}
+        <style:style style:name="chS{{$index}}" style:family="chart">
+            <style:graphic-properties draw:fill="solid" draw:fill-color="{{color .Color $index}}" svg:stroke-color="{{color .Color $index}}"{{if isLine}} svg:stroke-width="0.08cm"{{end}}/>
+        </style:style>
+        {{end}}
+        {{if isPie}}
+        {{range $index, $category := .Spec.Categories}}
+        <style:style style:name="chP{{$index}}" style:family="chart">
+            <style:graphic-properties draw:fill="solid" draw:fill-color="{{color "" $index}}"/>
+        </style:style>
+        {{end}}
+        {{end}}
+    </office:automatic-styles>
+    <office:body>
+        <office:chart>
+            <chart:chart svg:width="{{.Width}}" svg:height="{{.Height}}" chart:class="{{class}}" chart:style-name="ch1">
+                {{if .Spec.Title}}
+                <chart:title><text:p>{{escape .Spec.Title}}</text:p></chart:title>
+                {{end}}
+                {{if not .Spec.HideLegend}}
+                <chart:legend chart:legend-position="end" style:legend-expansion="high"/>
+                {{end}}
+                <chart:plot-area table:cell-range-address="local-table.$A$1:.${{column (len .Spec.Series)}}${{inc (len .Spec.Categories)}}" chart:data-source-has-labels="both">
+                    <chart:axis chart:dimension="x" chart:name="primary-x">
+                        {{if not isScatter}}
+                        <chart:categories table:cell-range-address="local-table.$A$2:.$A${{inc (len .Spec.Categories)}}"/>
+                        {{end}}
+                    </chart:axis>
+                    <chart:axis chart:dimension="y" chart:name="primary-y">
+                        <chart:grid chart:class="major"/>
+                    </chart:axis>
+                    {{$rows := inc (len .Spec.Categories)}}
+                    {{range $index, $series := .Spec.Series}}
+                    {{if or (not isPie) (eq $index 0)}}
+                    {{$column := column (inc $index)}}
+                    <chart:series chart:style-name="chS{{$index}}" chart:values-cell-range-address="local-table.${{$column}}$2:.${{$column}}${{$rows}}" chart:label-cell-address="local-table.${{$column}}$1" chart:class="{{class}}">
+                        {{if isScatter}}
+                        <chart:domain table:cell-range-address="local-table.$A$2:.$A${{$rows}}"/>
+                        {{end}}
+                        {{if isPie}}
+                        {{range $point, $category := $.Spec.Categories}}
+                        <chart:data-point chart:style-name="chP{{$point}}"/>
+                        {{end}}
+                        {{end}}
+                    </chart:series>
+                    {{end}}
+                    {{end}}
+                </chart:plot-area>
+            </chart:chart>
+            <table:table table:name="local-table">
+                <table:table-header-columns>
+                    <table:table-column/>
+                </table:table-header-columns>
+                <table:table-columns>
+                    <table:table-column table:number-columns-repeated="{{len .Spec.Series}}"/>
+                </table:table-columns>
+                <table:table-header-rows>
+                    <table:table-row>
+                        <table:table-cell><text:p/></table:table-cell>
+                        {{range .Spec.Series}}
+                        <table:table-cell office:value-type="string"><text:p>{{escape .Name}}</text:p></table:table-cell>
+                        {{end}}
+                    </table:table-row>
+                </table:table-header-rows>
+                <table:table-rows>
+                    {{range $row, $category := .Spec.Categories}}
+                    <table:table-row>
+                        {{if isScatter}}
+                        <table:table-cell office:value-type="float" office:value="{{$category}}"><text:p>{{$category}}</text:p></table:table-cell>
+                        {{else}}
+                        <table:table-cell office:value-type="string"><text:p>{{escape $category}}</text:p></table:table-cell>
+                        {{end}}
+                        {{range $.Spec.Series}}
+                        {{$value := index .Values $row}}
+                        <table:table-cell office:value-type="float" office:value="{{number $value}}"><text:p>{{number $value}}</text:p></table:table-cell>
+                        {{end}}
+                    </table:table-row>
+                    {{end}}
+                </table:table-rows>
+            </table:table>
+        </office:chart>
+    </office:body>
+</office:document-content>`
+
+	tmpl, err := template.New("chart").Funcs(template.FuncMap{
+		"class": func() string {
+			return chartClass(chart.Spec.Type)
+		},
+		"isPie": func() bool {
+			return chart.Spec.Type == ChartPie
+		},
+		"isLine": func() bool {
+			return chart.Spec.Type == ChartLine || chart.Spec.Type == ChartScatter
+		},
+		"isScatter": func() bool {
+			return chart.Spec.Type == ChartScatter
+		},
+		"color":  chartColor,
+		"column": chartColumn,
+		"escape": escapeXML,
+		"inc": func(i int) int {
+			return i + 1
+		},
+		"number": func(value float64) string {
+			return strconv.FormatFloat(value, 'g', -1, 64)
+		},
+	}).Parse(chartTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, chart); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juaismar/goodp"
+)
+
+// runBuild construye una presentación a partir de un archivo de descripción
+func runBuild(args []string) error {
+	flags := newFlagSet("build")
+	output := flags.String("o", "", "archivo de salida (por defecto, el de entrada con extensión .odp)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("se necesita un único archivo de descripción")
+	}
+
+	input := flags.Arg(0)
+	presentation, err := buildDeck(input)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		*output = strings.TrimSuffix(input, filepath.Ext(input)) + ".odp"
+	}
+	return presentation.Save(*output)
+}
+
+// buildDeck construye la presentación descrita por un archivo JSON o YAML, leyendo
+// las imágenes relativas a la carpeta del archivo
+func buildDeck(filename string) (*goodp.ODPGenerator, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	presentation, err := goodp.FromSpec(file, os.DirFS(filepath.Dir(filename)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return presentation, nil
+}
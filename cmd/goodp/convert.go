@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juaismar/goodp"
+	"github.com/juaismar/goodp/markdown"
+)
+
+// runConvert convierte una presentación a otro formato según la extensión de la salida
+func runConvert(args []string) error {
+	flags := newFlagSet("convert")
+	site := flags.Bool("site", false, "exportar a HTML como sitio estático en la carpeta de salida")
+	slideNumber := flags.Int("slide", 1, "diapositiva que se exporta a PNG, empezando en 1")
+	dpi := flags.Float64("dpi", 96, "resolución de la exportación a PNG, en puntos por pulgada")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("se necesitan un archivo de entrada y uno de salida")
+	}
+	input, output := flags.Arg(0), flags.Arg(1)
+
+	presentation, err := openDeck(input)
+	if err != nil {
+		return err
+	}
+	if *site {
+		return presentation.SaveHTMLSite(output)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(output)); ext {
+	case ".odp":
+		return presentation.Save(output)
+	case ".pptx":
+		return presentation.SavePPTX(output)
+	case ".pdf":
+		return writeFile(output, presentation.SavePDF)
+	case ".html", ".htm":
+		return writeFile(output, presentation.SaveHTML)
+	case ".json":
+		// Las imágenes se guardan junto a la descripción
+		return writeFile(output, func(w io.Writer) error {
+			return presentation.ToSpec(w, filepath.Dir(output))
+		})
+	case ".png":
+		if *slideNumber < 1 || *slideNumber > len(presentation.Slides) {
+			return fmt.Errorf("la presentación no tiene la diapositiva %d", *slideNumber)
+		}
+		img, err := presentation.RenderSlide(&presentation.Slides[*slideNumber-1], *dpi)
+		if err != nil {
+			return err
+		}
+		return writeFile(output, func(w io.Writer) error { return png.Encode(w, img) })
+	default:
+		return fmt.Errorf("formato de salida no soportado: %q (usa .odp, .pptx, .pdf, .html, .png o .json)", ext)
+	}
+}
+
+// openDeck abre una presentación .odp o la crea a partir de un documento Markdown
+// o de un archivo de descripción JSON o YAML
+func openDeck(filename string) (*goodp.ODPGenerator, error) {
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".odp":
+		return goodp.Open(filename)
+	case ".md", ".markdown":
+		return markdown.ConvertFile(filename)
+	case ".json", ".yaml", ".yml":
+		return buildDeck(filename)
+	default:
+		return nil, fmt.Errorf("formato de entrada no soportado: %q (usa .odp, .md, .json o .yaml)", ext)
+	}
+}
+
+// writeFile crea un archivo y escribe en él con write
+func writeFile(filename string, write func(io.Writer) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
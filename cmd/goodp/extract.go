@@ -0,0 +1,62 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// runExtract extrae las imágenes y los archivos multimedia de una presentación
+func runExtract(args []string) error {
+	flags := newFlagSet("extract")
+	output := flags.String("o", ".", "carpeta en la que se extraen los archivos")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("se necesita un único archivo .odp")
+	}
+
+	archive, err := zip.OpenReader(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		name := path.Clean(file.Name)
+		if !strings.HasPrefix(name, "Pictures/") && !strings.HasPrefix(name, "media/") {
+			continue
+		}
+		if strings.HasSuffix(file.Name, "/") || strings.Contains(name, "..") {
+			continue
+		}
+		target := filepath.Join(*output, filepath.FromSlash(name))
+		if err := extractFile(file, target); err != nil {
+			return err
+		}
+		fmt.Println(target)
+	}
+	return nil
+}
+
+// extractFile copia un archivo del paquete a target, creando sus carpetas
+func extractFile(file *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return writeFile(target, func(w io.Writer) error {
+		_, err := io.Copy(w, reader)
+		return err
+	})
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"  // Formatos de imagen para mostrar las dimensiones de las imágenes
+	_ "image/jpeg" // Formatos de imagen para mostrar las dimensiones de las imágenes
+	_ "image/png"  // Formatos de imagen para mostrar las dimensiones de las imágenes
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juaismar/goodp"
+)
+
+// excerptLength es el número máximo de caracteres del texto que se muestra de cada elemento
+const excerptLength = 40
+
+// unescapeText deshace el escapado XML del texto de los cuadros de texto
+var unescapeText = strings.NewReplacer("<text:line-break/>", " / ", "&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// runInspect muestra las diapositivas, los elementos, las imágenes y los estilos de
+// una presentación
+func runInspect(args []string) error {
+	flags := newFlagSet("inspect")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("se necesita un único archivo .odp")
+	}
+	presentation, err := goodp.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	metadata := presentation.Metadata
+	if metadata.Title != "" {
+		fmt.Fprintf(out, "Título:\t%s\n", metadata.Title)
+	}
+	if metadata.Creator != "" {
+		fmt.Fprintf(out, "Autor:\t%s\n", metadata.Creator)
+	}
+	fmt.Fprintf(out, "Tamaño:\t%.2f x %.2f cm\n", presentation.SlideSize.Width, presentation.SlideSize.Height)
+	fmt.Fprintf(out, "Fondo:\t%s\n", describeBackground(presentation.Background))
+	for _, master := range presentation.MasterPages {
+		fmt.Fprintf(out, "Página maestra:\t%s (fondo: %s, %d elementos)\n",
+			master.Name, describeBackground(master.Background), len(master.SortedElements()))
+	}
+	fmt.Fprintf(out, "Diapositivas:\t%d\n", len(presentation.Slides))
+	out.Flush()
+
+	for i, slide := range presentation.Slides {
+		fmt.Printf("\nDiapositiva %d", i+1)
+		if slide.ID != "" {
+			fmt.Printf(" (%s)", slide.ID)
+		}
+		if slide.MasterPage != "" {
+			fmt.Printf(" [página maestra %s]", slide.MasterPage)
+		}
+		fmt.Println()
+		if slide.Background != nil {
+			fmt.Printf("  Fondo: %s\n", describeBackground(slide.Background))
+		}
+		out = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, element := range slide.SortedElements() {
+			x, y, width, height, text := describeElement(element)
+			fmt.Fprintf(out, "  z=%d\t%s\t%s, %s\t%s x %s\t%s\n", element.ZIndex, element.Type, x, y, width, height, text)
+		}
+		out.Flush()
+		if len(slide.Notes) > 0 {
+			fmt.Printf("  Notas: %s\n", excerpt(paragraphsText(slide.Notes)))
+		}
+		if len(slide.Animations) > 0 {
+			fmt.Printf("  Animaciones: %d\n", len(slide.Animations))
+		}
+	}
+
+	printImages(presentation)
+	printStyles(presentation)
+	return nil
+}
+
+// describeBackground describe un fondo de color o de imagen
+func describeBackground(background *goodp.Background) string {
+	switch {
+	case background == nil:
+		return "ninguno"
+	case background.Type == goodp.BackgroundColor:
+		return "color " + background.Color
+	default:
+		return fmt.Sprintf("imagen %s (%s)", background.Name, describeImage(background.Data))
+	}
+}
+
+// describeElement devuelve la posición, el tamaño y un resumen del contenido de un elemento
+func describeElement(element goodp.DrawableElement) (x, y, width, height, text string) {
+	switch data := element.Data.(type) {
+	case goodp.TextBox:
+		content := unescapeText.Replace(data.Content)
+		if len(data.Paragraphs) > 0 {
+			content = paragraphsText(data.Paragraphs)
+		}
+		return data.X, data.Y, data.Width, data.Height, excerpt(content)
+	case goodp.Image:
+		return data.X, data.Y, data.Width, data.Height, data.Name
+	case goodp.List:
+		return data.X, data.Y, data.Width, data.Height, excerpt(paragraphsText(listParagraphs(data.Items)))
+	case goodp.Table:
+		columns := 0
+		if len(data.Rows) > 0 {
+			columns = len(data.Rows[0])
+		}
+		return data.X, data.Y, data.Width, data.Height, fmt.Sprintf("%d filas x %d columnas", len(data.Rows), columns)
+	case goodp.Shape:
+		if data.Type == goodp.ShapeLine {
+			return data.X1, data.Y1, data.X2, data.Y2, string(data.Type)
+		}
+		return data.X, data.Y, data.Width, data.Height, strings.TrimSpace(string(data.Type) + " " + excerpt(unescapeText.Replace(data.Text)))
+	case goodp.Chart:
+		return data.X, data.Y, data.Width, data.Height, strings.TrimSpace(string(data.Spec.Type) + " " + excerpt(data.Spec.Title))
+	}
+	return "", "", "", "", ""
+}
+
+// paragraphsText une el texto de unos párrafos
+func paragraphsText(paragraphs []goodp.Paragraph) string {
+	texts := make([]string, 0, len(paragraphs))
+	for _, paragraph := range paragraphs {
+		var text strings.Builder
+		for _, run := range paragraph.Runs {
+			text.WriteString(unescapeText.Replace(run.Text))
+		}
+		texts = append(texts, text.String())
+	}
+	return strings.Join(texts, " / ")
+}
+
+// listParagraphs devuelve un párrafo por cada elemento de una lista y de sus
+// sublistas
+func listParagraphs(items []goodp.ListItem) []goodp.Paragraph {
+	var paragraphs []goodp.Paragraph
+	for _, item := range items {
+		runs := item.Runs
+		if len(runs) == 0 {
+			runs = []goodp.Run{{Text: item.Text}}
+		}
+		paragraphs = append(paragraphs, goodp.Paragraph{Runs: runs})
+		paragraphs = append(paragraphs, listParagraphs(item.Items)...)
+	}
+	return paragraphs
+}
+
+// excerpt acorta un texto para mostrarlo en una línea
+func excerpt(text string) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) > excerptLength {
+		return fmt.Sprintf("%q", string(runes[:excerptLength])+"…")
+	}
+	if len(runes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%q", string(runes))
+}
+
+// describeImage devuelve el tamaño en bytes y, si se puede leer, en píxeles de una imagen
+func describeImage(data []byte) string {
+	if config, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		return fmt.Sprintf("%s, %dx%d px, %d bytes", format, config.Width, config.Height, len(data))
+	}
+	return fmt.Sprintf("%d bytes", len(data))
+}
+
+// printImages muestra las imágenes de las diapositivas y de las páginas maestras
+func printImages(presentation *goodp.ODPGenerator) {
+	seen := make(map[string]bool)
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	add := func(name string, data []byte) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		fmt.Fprintf(out, "  %s\t%s\n", name, describeImage(data))
+	}
+
+	if background := presentation.Background; background != nil && background.Type == goodp.BackgroundImage {
+		add(background.Name, background.Data)
+	}
+	for _, master := range presentation.MasterPages {
+		if master.Background != nil && master.Background.Type == goodp.BackgroundImage {
+			add(master.Background.Name, master.Background.Data)
+		}
+		for _, img := range master.Images {
+			add(img.Name, img.Data)
+		}
+	}
+	for _, slide := range presentation.Slides {
+		if slide.Background != nil && slide.Background.Type == goodp.BackgroundImage {
+			add(slide.Background.Name, slide.Background.Data)
+		}
+		for _, img := range slide.Images {
+			add(img.Name, img.Data)
+		}
+	}
+
+	fmt.Printf("\nImágenes: %d\n", len(seen))
+	out.Flush()
+}
+
+// printStyles muestra los estilos de texto usados en la presentación y cuántas veces
+// se usa cada uno
+func printStyles(presentation *goodp.ODPGenerator) {
+	counts := make(map[string]int)
+	add := func(style goodp.TextStyle) {
+		counts[describeStyle(style)]++
+	}
+	for _, slide := range presentation.Slides {
+		for _, tb := range slide.TextBoxes {
+			if len(tb.Paragraphs) == 0 {
+				add(tb.Style)
+			}
+			for _, paragraph := range tb.Paragraphs {
+				for _, run := range paragraph.Runs {
+					add(run.Style)
+				}
+			}
+		}
+		for _, list := range slide.Lists {
+			add(list.Style)
+		}
+		for _, shape := range slide.Shapes {
+			if shape.Text != "" {
+				add(shape.Style)
+			}
+		}
+	}
+
+	styles := make([]string, 0, len(counts))
+	for style := range counts {
+		styles = append(styles, style)
+	}
+	sort.Slice(styles, func(i, j int) bool {
+		if counts[styles[i]] != counts[styles[j]] {
+			return counts[styles[i]] > counts[styles[j]]
+		}
+		return styles[i] < styles[j]
+	})
+
+	fmt.Printf("\nEstilos de texto: %d\n", len(styles))
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, style := range styles {
+		fmt.Fprintf(out, "  %s\t%d usos\n", style, counts[style])
+	}
+	out.Flush()
+}
+
+// describeStyle describe un estilo de texto en una línea
+func describeStyle(style goodp.TextStyle) string {
+	parts := []string{}
+	for _, value := range []string{style.FontFamily, style.FontSize, style.Color} {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	if style.Bold {
+		parts = append(parts, "negrita")
+	}
+	if style.Italic {
+		parts = append(parts, "cursiva")
+	}
+	if len(parts) == 0 {
+		return "(por defecto)"
+	}
+	return strings.Join(parts, " ")
+}
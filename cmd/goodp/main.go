@@ -0,0 +1,122 @@
+// Command goodp crea y convierte presentaciones OpenDocument desde la línea de órdenes.
+//
+// Uso:
+//
+//	goodp <orden> [opciones] [argumentos]
+//
+// Ejecuta "goodp help" para ver las órdenes disponibles.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// command es una orden de la herramienta
+type command struct {
+	Name        string
+	Args        string // Argumentos que se muestran en la ayuda
+	Description string
+	Run         func(args []string) error
+}
+
+// commands son las órdenes disponibles, en el orden en que se muestran en la ayuda.
+// Se inicializan en init porque la ayuda de cada orden las consulta.
+var commands []command
+
+func init() {
+	commands = []command{
+		{
+			Name:        "build",
+			Args:        "[-o salida.odp] descripcion.{json,yaml}",
+			Description: "Construye una presentación a partir de un archivo de descripción JSON o YAML",
+			Run:         runBuild,
+		},
+		{
+			Name:        "inspect",
+			Args:        "archivo.odp",
+			Description: "Muestra las diapositivas, sus elementos, las imágenes y los estilos de texto",
+			Run:         runInspect,
+		},
+		{
+			Name:        "extract",
+			Args:        "[-o carpeta] archivo.odp",
+			Description: "Extrae las imágenes y los archivos multimedia de una presentación",
+			Run:         runExtract,
+		},
+		{
+			Name:        "convert",
+			Args:        "[-site] [-slide N] [-dpi D] entrada.{odp,md,json,yaml} salida.{odp,pptx,pdf,html,png,json}",
+			Description: "Convierte una presentación al formato que indica la extensión de la salida",
+			Run:         runConvert,
+		},
+		{
+			Name:        "set",
+			Args:        "[-o salida.odp] [-background #RRGGBB|imagen] [-size 16:9|4:3|ANCHOxALTO] archivo.odp",
+			Description: "Cambia el fondo de todas las diapositivas o su tamaño",
+			Run:         runSet,
+		},
+		{
+			Name:        "md2odp",
+			Args:        "[-o salida.odp] [-aspect 16:9|4:3] entrada.md",
+			Description: "Convierte un documento Markdown en una presentación",
+			Run:         runMd2odp,
+		},
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
+	for _, cmd := range commands {
+		if cmd.Name != name {
+			continue
+		}
+		if err := cmd.Run(os.Args[2:]); err != nil {
+			if !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintf(os.Stderr, "goodp %s: %v\n", name, err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "goodp: orden desconocida %q\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage muestra las órdenes disponibles
+func usage() {
+	fmt.Fprintln(os.Stderr, "Uso: goodp <orden> [opciones] [argumentos]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Órdenes:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.Name, cmd.Description)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Usa \"goodp <orden> -h\" para ver las opciones de cada orden.")
+}
+
+// newFlagSet crea el conjunto de opciones de una orden con su ayuda
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("goodp "+name, flag.ContinueOnError)
+	flags.Usage = func() {
+		for _, cmd := range commands {
+			if cmd.Name == name {
+				fmt.Fprintf(flags.Output(), "Uso: goodp %s %s\n\n%s\n\n", cmd.Name, cmd.Args, cmd.Description)
+			}
+		}
+		flags.PrintDefaults()
+	}
+	return flags
+}
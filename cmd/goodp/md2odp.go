@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juaismar/goodp"
+	"github.com/juaismar/goodp/markdown"
+)
+
+// runMd2odp convierte un documento Markdown en una presentación. Las imágenes se
+// leen relativas a la carpeta del documento.
+func runMd2odp(args []string) error {
+	flags := newFlagSet("md2odp")
+	output := flags.String("o", "", "archivo de salida (por defecto, el de entrada con extensión .odp)")
+	aspect := flags.String("aspect", goodp.AspectRatio169, "relación de aspecto de las diapositivas: 16:9 o 4:3")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("se necesita un único archivo Markdown")
+	}
+
+	input := flags.Arg(0)
+	source, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	presentation, err := markdown.Convert(source, &markdown.Options{
+		AspectRatio: *aspect,
+		Images:      os.DirFS(filepath.Dir(input)),
+	})
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		*output = strings.TrimSuffix(input, filepath.Ext(input)) + ".odp"
+	}
+	return presentation.Save(*output)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/juaismar/goodp"
+)
+
+// runSet cambia el fondo global o el tamaño de las diapositivas de una presentación
+func runSet(args []string) error {
+	flags := newFlagSet("set")
+	output := flags.String("o", "", "archivo de salida (por defecto, se sobrescribe el de entrada)")
+	background := flags.String("background", "", "fondo de todas las diapositivas: un color #RRGGBB o una imagen")
+	size := flags.String("size", "", "tamaño de las diapositivas: 16:9, 4:3 o ANCHOxALTO en cm")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("se necesita un único archivo .odp")
+	}
+	if *background == "" && *size == "" {
+		return fmt.Errorf("indica -background o -size")
+	}
+
+	input := flags.Arg(0)
+	presentation, err := goodp.Open(input)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(*background, "#") {
+		presentation.SetBackgroundColor(*background)
+	} else if *background != "" {
+		data, err := os.ReadFile(*background)
+		if err != nil {
+			return err
+		}
+		if err := presentation.SetBackgroundImage(data, filepath.Ext(*background)); err != nil {
+			return err
+		}
+	}
+
+	switch *size {
+	case "":
+	case goodp.AspectRatio169, goodp.AspectRatio43:
+		presentation.SetSlideSize(*size)
+	default:
+		width, height, err := parseSize(*size)
+		if err != nil {
+			return err
+		}
+		presentation.SetCustomSlideSize(width, height)
+	}
+
+	if *output == "" {
+		*output = input
+	}
+	return presentation.Save(*output)
+}
+
+// parseSize interpreta un tamaño "ANCHOxALTO" en cm
+func parseSize(value string) (width, height float64, err error) {
+	parts := strings.Split(strings.ToLower(value), "x")
+	if len(parts) == 2 {
+		width, errWidth := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		height, errHeight := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if errWidth == nil && errHeight == nil && width > 0 && height > 0 {
+			return width, height, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("tamaño inválido: %q (usa 16:9, 4:3 o ANCHOxALTO en cm)", value)
+}
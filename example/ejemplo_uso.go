@@ -27,7 +27,7 @@ func main() {
 	}
 
 	// Añadir la imagen (usando la extensión del archivo)
-	err = presentacion.AddImage(silde1, imageData, ".png", 15, 5, 10, 8)
+	_, err = presentacion.AddImage(silde1, imageData, ".png", 15, 5, 10, 8)
 	if err != nil {
 		log.Fatal(err)
 	}
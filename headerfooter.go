@@ -0,0 +1,217 @@
+package goodp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DateTimeFormat es el formato con el que se muestra la fecha actual en las diapositivas
+type DateTimeFormat string
+
+const (
+	DateShort    DateTimeFormat = "date"      // 16/10/2026
+	DateLong     DateTimeFormat = "date-long" // 16 de octubre de 2026
+	TimeShort    DateTimeFormat = "time"      // 09:30
+	DateTimeFull DateTimeFormat = "date-time" // 16/10/2026 09:30
+)
+
+// HeaderFooter define el pie de página, el número de diapositiva y la fecha que se
+// muestran en los marcadores de posición de la página maestra
+type HeaderFooter struct {
+	Footer         string         // Texto del pie de página (vacío para no mostrarlo)
+	SlideNumber    bool           // Mostrar el número de diapositiva
+	DateTime       bool           // Mostrar la fecha
+	FixedDateTime  string         // Texto fijo de la fecha; vacío para mostrar la fecha actual
+	DateTimeFormat DateTimeFormat // Formato de la fecha actual (por defecto DateShort)
+}
+
+// Nombres de los estilos de datos (number:date-style) de cada formato de fecha
+var dateTimeDataStyles = map[DateTimeFormat]string{
+	DateShort:    "goodpDate",
+	DateLong:     "goodpDateLong",
+	TimeShort:    "goodpTime",
+	DateTimeFull: "goodpDateTime",
+}
+
+// SetDefaultHeaderFooter establece el pie de página, el número y la fecha de todas las
+// diapositivas que no tienen uno propio
+func (g *ODPGenerator) SetDefaultHeaderFooter(headerFooter HeaderFooter) error {
+	prepared, err := prepareHeaderFooter(headerFooter)
+	if err != nil {
+		return err
+	}
+	g.HeaderFooter = prepared
+	return nil
+}
+
+// SetSlideHeaderFooter establece el pie de página, el número y la fecha de una
+// diapositiva. Con HeaderFooter{} la diapositiva no muestra ninguno de ellos.
+func (g *ODPGenerator) SetSlideHeaderFooter(slide *Slide, headerFooter HeaderFooter) error {
+	if g.slideIndex(slide) == -1 {
+		return fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	prepared, err := prepareHeaderFooter(headerFooter)
+	if err != nil {
+		return err
+	}
+	slide.HeaderFooter = prepared
+	return nil
+}
+
+// prepareHeaderFooter valida el pie de página y completa los valores por defecto
+func prepareHeaderFooter(headerFooter HeaderFooter) (*HeaderFooter, error) {
+	if headerFooter.DateTimeFormat == "" {
+		headerFooter.DateTimeFormat = DateShort
+	}
+	if _, ok := dateTimeDataStyles[headerFooter.DateTimeFormat]; !ok {
+		return nil, fmt.Errorf("formato de fecha no soportado: %q", headerFooter.DateTimeFormat)
+	}
+	if strings.Contains(headerFooter.Footer, "\n") || strings.Contains(headerFooter.FixedDateTime, "\n") {
+		return nil, fmt.Errorf("el pie de página y la fecha deben ocupar una sola línea")
+	}
+	return &headerFooter, nil
+}
+
+// slideHeaderFooter devuelve el pie de página que usa una diapositiva, o nil si no tiene
+func (g *ODPGenerator) slideHeaderFooter(slide Slide) *HeaderFooter {
+	if slide.HeaderFooter != nil {
+		return slide.HeaderFooter
+	}
+	return g.HeaderFooter
+}
+
+// usesHeaderFooter indica si alguna diapositiva muestra el pie de página, el número o la fecha
+func (g *ODPGenerator) usesHeaderFooter() bool {
+	for _, slide := range g.Slides {
+		if hf := g.slideHeaderFooter(slide); hf != nil && (hf.Footer != "" || hf.SlideNumber || hf.DateTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// displayAttributes genera los atributos de un estilo de página que indican qué
+// marcadores de posición de la página maestra se muestran
+func displayAttributes(headerFooter *HeaderFooter) string {
+	var hf HeaderFooter
+	if headerFooter != nil {
+		hf = *headerFooter
+	}
+	return fmt.Sprintf(`presentation:display-header="false" presentation:display-footer="%t" presentation:display-page-number="%t" presentation:display-date-time="%t"`,
+		hf.Footer != "", hf.SlideNumber, hf.DateTime)
+}
+
+// headerFooterDecl es la declaración de un texto de pie de página o de una fecha,
+// compartida por las diapositivas que la usan
+type headerFooterDecl struct {
+	Name      string
+	Text      string // Texto escapado (pie de página o fecha fija)
+	Source    string // "fixed" o "current" (solo fechas)
+	DataStyle string // Estilo de datos de la fecha actual
+}
+
+// headerFooterDecls agrupa las declaraciones de pies de página y fechas
+type headerFooterDecls struct {
+	Footers   []headerFooterDecl
+	DateTimes []headerFooterDecl
+	Slides    []slideDecls // Declaraciones que usa cada diapositiva
+}
+
+// slideDecls son los nombres de las declaraciones que usa una diapositiva
+type slideDecls struct {
+	Footer   string
+	DateTime string
+}
+
+// headerFooterDecls calcula las declaraciones de pies de página y fechas sin repetir
+func (g *ODPGenerator) headerFooterDecls() headerFooterDecls {
+	var decls headerFooterDecls
+	footers := make(map[string]string)
+	dateTimes := make(map[string]string)
+
+	for _, slide := range g.Slides {
+		var names slideDecls
+		hf := g.slideHeaderFooter(slide)
+		if hf != nil && hf.Footer != "" {
+			name, ok := footers[hf.Footer]
+			if !ok {
+				name = fmt.Sprintf("ftr%d", len(footers)+1)
+				footers[hf.Footer] = name
+				decls.Footers = append(decls.Footers, headerFooterDecl{Name: name, Text: escapeXML(hf.Footer)})
+			}
+			names.Footer = name
+		}
+		if hf != nil && hf.DateTime {
+			decl := headerFooterDecl{Source: "current", DataStyle: dateTimeDataStyles[hf.DateTimeFormat]}
+			if hf.FixedDateTime != "" {
+				decl = headerFooterDecl{Source: "fixed", Text: escapeXML(hf.FixedDateTime)}
+			}
+			key := decl.Source + "\x00" + decl.Text + "\x00" + decl.DataStyle
+			name, ok := dateTimes[key]
+			if !ok {
+				name = fmt.Sprintf("dtd%d", len(dateTimes)+1)
+				dateTimes[key] = name
+				decl.Name = name
+				decls.DateTimes = append(decls.DateTimes, decl)
+			}
+			names.DateTime = name
+		}
+		decls.Slides = append(decls.Slides, names)
+	}
+	return decls
+}
+
+// defaultFooterPlaceholders devuelve los marcadores de posición de la fecha, el pie de
+// página y el número de diapositiva en la parte inferior de la diapositiva, con la
+// disposición de Impress, sin los que ya tiene la página maestra
+func (g *ODPGenerator) defaultFooterPlaceholders(existing []Placeholder) []Placeholder {
+	if !g.usesHeaderFooter() {
+		return nil
+	}
+	has := make(map[PlaceholderClass]bool)
+	for _, p := range existing {
+		has[p.Class] = true
+	}
+
+	const margin, height = 1.4, 1.09
+	width := g.SlideSize.Width
+	y := g.SlideSize.Height - height - 0.33
+	sideWidth := width * 0.232
+	footerWidth := width * 0.317
+
+	var placeholders []Placeholder
+	add := func(class PlaceholderClass, x, w float64) {
+		if has[class] {
+			return
+		}
+		placeholders = append(placeholders, Placeholder{
+			Class:  class,
+			X:      fmt.Sprintf("%.2fcm", x),
+			Y:      fmt.Sprintf("%.2fcm", y),
+			Width:  fmt.Sprintf("%.2fcm", w),
+			Height: fmt.Sprintf("%.2fcm", height),
+		})
+	}
+	add(PlaceholderDateTime, margin, sideWidth)
+	add(PlaceholderFooter, (width-footerWidth)/2, footerWidth)
+	add(PlaceholderPageNumber, width-margin-sideWidth, sideWidth)
+	return placeholders
+}
+
+// dateTimeStyles contiene los estilos de datos de los formatos de fecha
+const dateTimeStyles = `
+{{define "dateTimeStyles"}}
+        <number:date-style style:name="goodpDate">
+            <number:day number:style="long"/><number:text>/</number:text><number:month number:style="long"/><number:text>/</number:text><number:year number:style="long"/>
+        </number:date-style>
+        <number:date-style style:name="goodpDateLong" number:language="es">
+            <number:day/><number:text> de </number:text><number:month number:textual="true" number:style="long"/><number:text> de </number:text><number:year number:style="long"/>
+        </number:date-style>
+        <number:time-style style:name="goodpTime">
+            <number:hours number:style="long"/><number:text>:</number:text><number:minutes number:style="long"/>
+        </number:time-style>
+        <number:date-style style:name="goodpDateTime">
+            <number:day number:style="long"/><number:text>/</number:text><number:month number:style="long"/><number:text>/</number:text><number:year number:style="long"/><number:text> </number:text><number:hours number:style="long"/><number:text>:</number:text><number:minutes number:style="long"/>
+        </number:date-style>
+{{end}}
+`
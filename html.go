@@ -0,0 +1,358 @@
+package goodp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// cssPixelsPerCm son los píxeles CSS que hay en un centímetro
+const cssPixelsPerCm = 96 / 2.54
+
+// Alineaciones verticales de CSS (justify-content) equivalentes a las de TextProperties
+var htmlVerticalAligns = map[string]string{
+	"top":    "flex-start",
+	"middle": "center",
+	"center": "center",
+	"bottom": "flex-end",
+}
+
+// cssURL escapa los caracteres que no pueden aparecer en un url('...') dentro de un atributo style
+var cssURL = strings.NewReplacer("'", "%27", "\"", "%22", "&", "%26", "<", "%3C", " ", "%20")
+
+// SaveHTML escribe la presentación como un único archivo HTML autocontenido, con las
+// imágenes incrustadas, que se puede abrir en cualquier navegador
+func (g *ODPGenerator) SaveHTML(w io.Writer) error {
+	return g.writeHTML(w, func(name string, data []byte) (string, error) {
+		extension := strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+		contentType, ok := imageContentTypes[extension]
+		if !ok {
+			contentType = "application/octet-stream"
+		}
+		return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+	})
+}
+
+// SaveHTMLSite guarda la presentación como un sitio web estático en el directorio dir:
+// index.html y las imágenes, con las mismas rutas que dentro del archivo .odp
+func (g *ODPGenerator) SaveHTMLSite(dir string) error {
+	files := make(map[string][]byte)
+	var buf bytes.Buffer
+	err := g.writeHTML(&buf, func(name string, data []byte) (string, error) {
+		clean, err := cleanImagePath(name)
+		if err != nil {
+			return "", err
+		}
+		files[clean] = data
+		return clean, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, data := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filename, data, 0644); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filepath.Join(dir, "index.html"), buf.Bytes(), 0644)
+}
+
+// writeHTML genera la página HTML de la presentación. imageURL devuelve la dirección
+// con la que se enlaza cada imagen (incrustada o como archivo aparte).
+func (g *ODPGenerator) writeHTML(writer io.Writer, imageURL func(name string, data []byte) (string, error)) error {
+	if err := g.validateClickActions(); err != nil {
+		return err
+	}
+
+	htmlTemplate := `<!DOCTYPE html>
+<html{{with .Metadata.Language}} lang="{{html .}}"{{end}}>
+<head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <meta name="generator" content="{{generator}}">
+    <title>{{html (title)}}</title>
+    <style>
+        html, body { margin: 0; height: 100%; overflow: hidden; background: #000; }
+        .slide {
+            position: absolute; left: 50%; top: 50%; display: none; overflow: hidden;
+            width: {{cm .SlideSize.Width}}; height: {{cm .SlideSize.Height}};
+            background-color: #fff; background-size: 100% 100%; {{with .Background}}{{background .}}{{end}}
+        }
+        .slide.active { display: block; }
+        .element { position: absolute; box-sizing: border-box; display: block; }
+        .textbox { display: flex; flex-direction: column; padding: {{cm 0.125}} {{cm 0.25}}; }
+        .textbox p { margin: 0; line-height: 1.17; white-space: pre-wrap; overflow-wrap: break-word; }
+        .textbox a { color: inherit; }
+    </style>
+</head>
+<body>
+{{range $slideIndex, $slide := .Slides}}
+    <section class="slide" id="{{html (pageName $slideIndex)}}"{{with slideBackground $slide}} style="{{background .}}"{{end}}>
+        {{with master $slide}}{{range .SortedElements}}{{template "element" (elementData . nil)}}{{end}}{{end}}
+        {{range .SortedElements}}{{template "element" (elementData . (clickAction $slideIndex .ZIndex))}}{{end}}
+    </section>
+{{end}}
+    <script>
+    (function () {
+        var slides = Array.prototype.slice.call(document.querySelectorAll(".slide"));
+        var width = {{pixels .SlideSize.Width}}, height = {{pixels .SlideSize.Height}};
+        var current = 0;
+        if (!slides.length) {
+            return;
+        }
+
+        function fit() {
+            var scale = Math.min(window.innerWidth / width, window.innerHeight / height);
+            slides.forEach(function (slide) {
+                slide.style.transform = "translate(-50%, -50%) scale(" + scale + ")";
+            });
+        }
+
+        function show(index) {
+            if (!(index >= 0 && index < slides.length)) {
+                return;
+            }
+            slides[current].classList.remove("active");
+            current = index;
+            slides[current].classList.add("active");
+            if (window.history && history.replaceState) {
+                history.replaceState(null, "", "#" + encodeURIComponent(slides[current].id));
+            }
+        }
+
+        function showHash() {
+            var id = decodeURIComponent(location.hash.slice(1));
+            for (var i = 0; i < slides.length; i++) {
+                if (slides[i].id === id) {
+                    show(i);
+                }
+            }
+        }
+
+        document.addEventListener("keydown", function (event) {
+            switch (event.key) {
+            case "ArrowRight": case "ArrowDown": case "PageDown": case " ": case "Enter":
+                show(current + 1);
+                break;
+            case "ArrowLeft": case "ArrowUp": case "PageUp": case "Backspace":
+                show(current - 1);
+                break;
+            case "Home":
+                show(0);
+                break;
+            case "End":
+                show(slides.length - 1);
+                break;
+            default:
+                return;
+            }
+            event.preventDefault();
+        });
+
+        document.addEventListener("click", function (event) {
+            var link = event.target.closest("[data-go]");
+            if (!link) {
+                return;
+            }
+            event.preventDefault();
+            var targets = {"next-page": current + 1, "previous-page": current - 1, "first-page": 0, "last-page": slides.length - 1};
+            show(targets[link.getAttribute("data-go")]);
+        });
+
+        window.addEventListener("hashchange", showHash);
+        window.addEventListener("resize", fit);
+        fit();
+        slides[0].classList.add("active");
+        showHash();
+    })();
+    </script>
+</body>
+</html>`
+
+	funcs := template.FuncMap{
+		"generator": func() string {
+			return metaGenerator
+		},
+		"title": func() string {
+			if g.Metadata.Title != "" {
+				return g.Metadata.Title
+			}
+			return "Presentación"
+		},
+		"cm": func(value float64) string {
+			return fmt.Sprintf("%.3fcm", value)
+		},
+		"pixels": func(value float64) string {
+			return fmt.Sprintf("%.2f", value*cssPixelsPerCm)
+		},
+		"pageName": g.pageName,
+		"slideBackground": func(slide Slide) *Background {
+			// El fondo de la presentación está en la regla .slide
+			if background := g.slideBackground(slide); background != g.Background {
+				return background
+			}
+			return nil
+		},
+		"background": func(background *Background) (string, error) {
+			if background.Type == BackgroundImage {
+				url, err := imageURL(background.Name, background.Data)
+				return fmt.Sprintf("background-image: url('%s');", cssURL.Replace(url)), err
+			}
+			if color := pptxColor(background.Color); color != "" {
+				return "background-color: #" + color + ";", nil
+			}
+			return "", nil
+		},
+		"master": func(slide Slide) *MasterPage {
+			return g.slideMaster(slide)
+		},
+		"clickAction": func(slideIndex, zIndex int) *ClickAction {
+			return g.clickAction(fmt.Sprint(slideIndex), zIndex)
+		},
+		"elementData": func(element DrawableElement, action *ClickAction) map[string]interface{} {
+			return map[string]interface{}{"Element": element, "Action": action}
+		},
+		"emptyRuns": func(runs []Run) bool {
+			for _, run := range runs {
+				if run.Text != "" {
+					return false
+				}
+			}
+			return true
+		},
+		"frameStyle": func(x, y, width, height string, zIndex int) (string, error) {
+			frame, err := pointsFrame(x, y, width, height)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("left: %.2fcm; top: %.2fcm; width: %.2fcm; height: %.2fcm; z-index: %d;",
+				frame.X/pointsPerCm, frame.Y/pointsPerCm, frame.Width/pointsPerCm, frame.Height/pointsPerCm, zIndex), nil
+		},
+		"verticalAlign": func(props *TextProperties) string {
+			if props != nil {
+				if align, ok := htmlVerticalAligns[props.VerticalAlign]; ok {
+					return align
+				}
+			}
+			return "flex-start"
+		},
+		"paragraphs":     textBoxParagraphs,
+		"paragraphStyle": htmlParagraphStyle,
+		"textStyle":      htmlTextStyle,
+		"text": func(text string) string {
+			// El texto ya está escapado para XML, que también es válido en HTML
+			return strings.ReplaceAll(text, "<text:line-break/>", "<br>")
+		},
+		"image": imageURL,
+		"safeLink": func(link string) string {
+			// Solo se enlazan los esquemas soportados, para no ejecutar javascript: y similares
+			if validateLink(link) != nil {
+				return ""
+			}
+			return link
+		},
+		"actionAttributes": func(action ClickAction) string {
+			switch action.Type {
+			case ActionURL:
+				if validateLink(action.URL) != nil {
+					return `href="#"`
+				}
+				return fmt.Sprintf(`href="%s" target="_blank" rel="noopener"`, html.EscapeString(action.URL))
+			case ActionSlide:
+				target, _ := g.actionTarget(action)
+				return fmt.Sprintf(`href="#%s"`, html.EscapeString(target))
+			}
+			return fmt.Sprintf(`href="#" data-go="%s"`, action.Type)
+		},
+	}
+
+	tmpl, err := template.New("html").Funcs(funcs).Parse(htmlTemplate + htmlElementTemplates)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(writer, g)
+}
+
+// htmlParagraphStyle genera el estilo CSS de un párrafo a partir de sus propiedades
+// o, si no tiene, de las del cuadro de texto
+func htmlParagraphStyle(paragraph Paragraph, box *TextProperties) string {
+	props := paragraph.Props
+	if props == nil {
+		props = box
+	}
+	if props == nil {
+		props = NewDefaultTextProperties()
+	}
+	var style []string
+	switch props.HorizontalAlign {
+	case "center", "right", "justify":
+		style = append(style, "text-align: "+props.HorizontalAlign+";")
+	case "end":
+		style = append(style, "text-align: right;")
+	}
+	if props.LeftIndent != 0 {
+		style = append(style, fmt.Sprintf("margin-left: %.2fcm;", props.LeftIndent))
+	}
+	if props.RightIndent != 0 {
+		style = append(style, fmt.Sprintf("margin-right: %.2fcm;", props.RightIndent))
+	}
+	if props.FirstLineIndent != 0 {
+		style = append(style, fmt.Sprintf("text-indent: %.2fcm;", props.FirstLineIndent))
+	}
+	return strings.Join(style, " ")
+}
+
+// htmlTextStyle genera el estilo CSS de un fragmento de texto
+func htmlTextStyle(style TextStyle) string {
+	css := []string{fmt.Sprintf("font-size: %.2fpt;", fontSizePoints(style))}
+	if style.FontFamily != "" {
+		css = append(css, fmt.Sprintf("font-family: '%s', sans-serif;", strings.ReplaceAll(html.EscapeString(style.FontFamily), "'", "")))
+	}
+	if color := pptxColor(style.Color); color != "" {
+		css = append(css, "color: #"+color+";")
+	}
+	if style.Bold {
+		css = append(css, "font-weight: bold;")
+	}
+	if style.Italic {
+		css = append(css, "font-style: italic;")
+	}
+	return strings.Join(css, " ")
+}
+
+// htmlElementTemplates contiene las plantillas de los elementos de una diapositiva.
+// Solo se exportan los cuadros de texto y las imágenes. Los enlaces del texto se omiten
+// si todo el cuadro es un enlace, porque HTML no permite enlaces anidados.
+const htmlElementTemplates = `
+{{define "element"}}
+        {{$action := .Action}}
+        {{with .Element}}
+        {{if eq .Type "textbox"}}{{with .Data}}
+        <{{if $action}}a {{actionAttributes $action}}{{else}}div{{end}} class="element textbox" style="{{frameStyle .X .Y .Width .Height .ZIndex}} justify-content: {{verticalAlign .Props}};">
+            {{$box := .}}
+            {{range paragraphs .}}
+            <p{{with paragraphStyle . $box.Props}} style="{{.}}"{{end}}>{{range .Runs}}{{$link := and (not $action) (safeLink .Link)}}{{if $link}}<a href="{{html $link}}" target="_blank" rel="noopener">{{end}}<span style="{{textStyle .Style}}">{{text .Text}}</span>{{if $link}}</a>{{end}}{{end}}{{if emptyRuns .Runs}}<br>{{end}}</p>
+            {{end}}
+        </{{if $action}}a{{else}}div{{end}}>
+        {{end}}{{else if eq .Type "image"}}{{with .Data}}
+        {{if $action}}<a {{actionAttributes $action}}>{{end}}<img class="element" src="{{html (image .Name .Data)}}" alt="" style="{{frameStyle .X .Y .Width .Height .ZIndex}}">{{if $action}}</a>{{end}}
+        {{end}}{{end}}
+        {{end}}
+{{end}}
+`
@@ -0,0 +1,206 @@
+package goodp
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Esquemas de dirección que se pueden usar en los enlaces
+var linkSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+	"ftp":    true,
+}
+
+// ActionType indica qué ocurre al hacer clic en un elemento durante la presentación
+type ActionType string
+
+const (
+	ActionURL           ActionType = "url"           // Abrir una dirección externa
+	ActionSlide         ActionType = "slide"         // Ir a una diapositiva concreta
+	ActionNextSlide     ActionType = "next-page"     // Ir a la siguiente diapositiva
+	ActionPreviousSlide ActionType = "previous-page" // Volver a la diapositiva anterior
+	ActionFirstSlide    ActionType = "first-page"    // Ir a la primera diapositiva
+	ActionLastSlide     ActionType = "last-page"     // Ir a la última diapositiva
+	ActionEndShow       ActionType = "stop"          // Terminar la presentación
+)
+
+// ClickAction es la acción que se ejecuta al hacer clic en un elemento durante la presentación
+type ClickAction struct {
+	Type    ActionType // Por defecto ActionURL
+	URL     string     // Dirección que se abre con ActionURL (http, https, mailto o ftp)
+	Slide   int        // Índice de la diapositiva destino con ActionSlide (empezando en 0)
+	SlideID string     // ID de la diapositiva destino con ActionSlide (tiene preferencia sobre Slide)
+}
+
+// SetLink hace que el elemento de la diapositiva con el Z-index indicado abra una
+// dirección al hacer clic en él durante la presentación. Con una dirección vacía se
+// quita el enlace.
+func (g *ODPGenerator) SetLink(slide *Slide, zIndex int, link string) error {
+	if link == "" {
+		return g.SetClickAction(slide, zIndex, nil)
+	}
+	return g.SetClickAction(slide, zIndex, &ClickAction{Type: ActionURL, URL: link})
+}
+
+// SetClickAction establece la acción que se ejecuta al hacer clic en el elemento de la
+// diapositiva con el Z-index indicado: abrir una dirección, ir a otra diapositiva o
+// terminar la presentación. Con action nil se quita la acción.
+func (g *ODPGenerator) SetClickAction(slide *Slide, zIndex int, action *ClickAction) error {
+	if g.slideIndex(slide) == -1 {
+		return fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	if !slide.hasElement(zIndex) {
+		return fmt.Errorf("la diapositiva no tiene ningún elemento con Z-index %d", zIndex)
+	}
+	if action == nil {
+		delete(slide.ClickActions, zIndex)
+		return nil
+	}
+
+	prepared := *action
+	if prepared.Type == "" {
+		prepared.Type = ActionURL
+	}
+	switch prepared.Type {
+	case ActionURL:
+		if err := validateLink(prepared.URL); err != nil {
+			return err
+		}
+	case ActionSlide:
+		// El destino se comprueba al guardar, porque puede ser una diapositiva que aún no existe
+		if prepared.SlideID == "" && prepared.Slide < 0 {
+			return fmt.Errorf("índice de diapositiva destino inválido: %d", prepared.Slide)
+		}
+	case ActionNextSlide, ActionPreviousSlide, ActionFirstSlide, ActionLastSlide, ActionEndShow:
+	default:
+		return fmt.Errorf("tipo de acción no soportado: %q", prepared.Type)
+	}
+
+	if slide.ClickActions == nil {
+		slide.ClickActions = make(map[int]ClickAction)
+	}
+	slide.ClickActions[zIndex] = prepared
+	return nil
+}
+
+// SetSlideID asigna a la diapositiva un identificador estable, que se usa como nombre
+// de la página y sirve como destino de ActionSlide aunque cambie su posición
+func (g *ODPGenerator) SetSlideID(slide *Slide, id string) error {
+	slideIndex := g.slideIndex(slide)
+	if slideIndex == -1 {
+		return fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("el identificador de la diapositiva no puede estar vacío")
+	}
+	if isGeneratedPageName(id) {
+		return fmt.Errorf("el identificador %q está reservado para los nombres de página que genera goodp", id)
+	}
+	for i, other := range g.Slides {
+		if i != slideIndex && other.ID == id {
+			return fmt.Errorf("el identificador %q ya lo usa la diapositiva %d", id, i)
+		}
+	}
+	slide.ID = id
+	return nil
+}
+
+// isGeneratedPageName indica si name tiene la forma de los nombres de página que
+// genera goodp ("page0", "page1"...)
+func isGeneratedPageName(name string) bool {
+	index := strings.TrimPrefix(name, "page")
+	if index == name || index == "" {
+		return false
+	}
+	_, err := strconv.Atoi(index)
+	return err == nil
+}
+
+// pageName devuelve el nombre de la página de una diapositiva: su ID o "page{índice}"
+func (g *ODPGenerator) pageName(slideIndex int) string {
+	if id := g.Slides[slideIndex].ID; id != "" {
+		return id
+	}
+	return fmt.Sprintf("page%d", slideIndex)
+}
+
+// actionTarget devuelve el nombre de la página a la que lleva una acción ActionSlide
+func (g *ODPGenerator) actionTarget(action ClickAction) (string, error) {
+	if action.SlideID != "" {
+		for i, slide := range g.Slides {
+			if slide.ID == action.SlideID {
+				return g.pageName(i), nil
+			}
+		}
+		return "", fmt.Errorf("no existe ninguna diapositiva con el identificador %q", action.SlideID)
+	}
+	if action.Slide < 0 || action.Slide >= len(g.Slides) {
+		return "", fmt.Errorf("la diapositiva destino %d no existe (hay %d diapositivas)", action.Slide, len(g.Slides))
+	}
+	return g.pageName(action.Slide), nil
+}
+
+// validateClickActions comprueba que existen las diapositivas destino de las acciones
+func (g *ODPGenerator) validateClickActions() error {
+	for i, slide := range g.Slides {
+		for _, action := range slide.ClickActions {
+			if action.Type != ActionSlide {
+				continue
+			}
+			if _, err := g.actionTarget(action); err != nil {
+				return fmt.Errorf("acción de la diapositiva %d: %v", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// actionAttributes genera los atributos del presentation:event-listener de una acción
+func (g *ODPGenerator) actionAttributes(action ClickAction) string {
+	switch action.Type {
+	case ActionURL:
+		return fmt.Sprintf(`presentation:action="show" xlink:href="%s" xlink:type="simple" xlink:show="embed" xlink:actuate="onRequest"`,
+			html.EscapeString(action.URL))
+	case ActionSlide:
+		target, _ := g.actionTarget(action)
+		return fmt.Sprintf(`presentation:action="show" xlink:href="#%s" xlink:type="simple" xlink:show="embed" xlink:actuate="onRequest"`,
+			html.EscapeString(target))
+	}
+	return fmt.Sprintf(`presentation:action="%s"`, action.Type)
+}
+
+// ValidLink indica si una dirección se puede usar como enlace: tiene que ser absoluta
+// y usar uno de los esquemas soportados (http, https, mailto o ftp)
+func ValidLink(link string) bool {
+	return validateLink(link) == nil
+}
+
+// validateLink comprueba que una dirección sea absoluta y use un esquema soportado
+func validateLink(link string) error {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("dirección de enlace inválida %q: %v", link, err)
+	}
+	if !linkSchemes[strings.ToLower(parsed.Scheme)] {
+		return fmt.Errorf("esquema de enlace no soportado en %q: debe ser http, https, mailto o ftp", link)
+	}
+	return nil
+}
+
+// clickAction devuelve la acción del elemento de una diapositiva, o nil si no tiene
+func (g *ODPGenerator) clickAction(scope string, zIndex int) *ClickAction {
+	slide := g.scopeSlide(scope)
+	if slide == nil {
+		return nil
+	}
+	action, ok := slide.ClickActions[zIndex]
+	if !ok {
+		return nil
+	}
+	return &action
+}
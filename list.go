@@ -0,0 +1,242 @@
+package goodp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxListLevels es el número máximo de niveles de lista que admite OpenDocument
+const maxListLevels = 10
+
+// List es una lista con viñetas o numerada, con elementos anidados en varios niveles
+type List struct {
+	Ordered bool        // Numerada (true) o con viñetas (false)
+	Items   []ListItem  // Elementos del primer nivel
+	Levels  []ListLevel // Formato de cada nivel (opcional, el índice 0 es el primer nivel)
+	X       string      // Posición X en cm
+	Y       string      // Posición Y en cm
+	Width   string      // Ancho en cm
+	Height  string      // Alto en cm
+	Style   TextStyle
+	Props   *TextProperties
+	ZIndex  int
+}
+
+// ListItem es un elemento de una lista. Si Runs no está vacío se usa en lugar de Text.
+type ListItem struct {
+	Text  string
+	Runs  []Run
+	Items []ListItem // Subelementos (siguiente nivel de la lista)
+}
+
+// ListLevel define el formato de un nivel de la lista. Si Numbering no está vacío el
+// nivel es numerado; si no, se usa la imagen de viñeta o, en su defecto, BulletChar.
+type ListLevel struct {
+	BulletChar           string // Carácter de viñeta (por defecto "•")
+	Numbering            string // Formato de numeración: "1.", "a)", "i.", "(A)"...
+	BulletImage          []byte // Imagen usada como viñeta (opcional)
+	BulletImageExtension string // Extensión de la imagen de viñeta, con el punto
+	BulletImageName      string // Ruta de la imagen dentro del paquete (la asigna AddList)
+}
+
+// Formatos por defecto de cada nivel, que se repiten cíclicamente
+var (
+	defaultBullets   = []string{"•", "–", "◦"}
+	defaultNumbering = []string{"1.", "a)", "i."}
+)
+
+// AddList añade una lista a la diapositiva especificada y devuelve un puntero a ella.
+// El texto usa el estilo actual de la diapositiva (SetTextStyle).
+func (g *ODPGenerator) AddList(slide *Slide, list List, x, y, width, height float64, zIndex ...int) (*List, error) {
+	slideIndex := g.slideIndex(slide)
+	if slideIndex == -1 {
+		return nil, fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("la lista no tiene elementos")
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("las dimensiones de la lista deben ser positivas")
+	}
+
+	depth := listDepth(list.Items)
+	if depth > maxListLevels {
+		return nil, fmt.Errorf("la lista tiene %d niveles, el máximo es %d", depth, maxListLevels)
+	}
+	if len(list.Levels) > depth {
+		depth = len(list.Levels)
+	}
+	if depth > maxListLevels {
+		depth = maxListLevels
+	}
+
+	// Completar el formato de todos los niveles usados
+	levels := make([]ListLevel, depth)
+	for i := range levels {
+		if i < len(list.Levels) {
+			levels[i] = list.Levels[i]
+		} else if list.Ordered {
+			levels[i] = ListLevel{Numbering: defaultNumbering[i%len(defaultNumbering)]}
+		} else {
+			levels[i] = ListLevel{BulletChar: defaultBullets[i%len(defaultBullets)]}
+		}
+
+		level := &levels[i]
+		switch {
+		case level.Numbering != "":
+			if _, _, _, err := parseNumbering(level.Numbering); err != nil {
+				return nil, err
+			}
+		case len(level.BulletImage) > 0:
+			extension, err := normalizeImageExtension(level.BulletImageExtension)
+			if err != nil {
+				return nil, err
+			}
+			level.BulletImageExtension = extension
+		case level.BulletChar == "":
+			level.BulletChar = defaultBullets[0]
+		}
+	}
+
+	zIndexValue := slide.getNextZIndex(zIndex...)
+	for i := range levels {
+		if levels[i].Numbering == "" && len(levels[i].BulletImage) > 0 {
+			levels[i].BulletImageName = fmt.Sprintf("Pictures/slide%d_list%d_level%d%s",
+				slideIndex, zIndexValue, i+1, levels[i].BulletImageExtension)
+		}
+	}
+
+	props := list.Props
+	if props == nil {
+		props = NewDefaultTextProperties()
+	}
+
+	items, err := prepareListItems(list.Items, slide.currentStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	added := &List{
+		Ordered: list.Ordered,
+		Items:   items,
+		Levels:  levels,
+		X:       fmt.Sprintf("%.2fcm", x),
+		Y:       fmt.Sprintf("%.2fcm", y),
+		Width:   fmt.Sprintf("%.2fcm", width),
+		Height:  fmt.Sprintf("%.2fcm", height),
+		Style:   slide.currentStyle,
+		Props:   props,
+		ZIndex:  zIndexValue,
+	}
+	slide.Lists = append(slide.Lists, added)
+	return added, nil
+}
+
+// prepareListItems copia los elementos de la lista convirtiendo su texto en
+// fragmentos escapados con el estilo base
+func prepareListItems(items []ListItem, base TextStyle) ([]ListItem, error) {
+	prepared := make([]ListItem, 0, len(items))
+	for _, item := range items {
+		runs := item.Runs
+		if len(runs) == 0 {
+			runs = []Run{{Text: item.Text}}
+		}
+		paragraphs, err := prepareParagraphs([]Paragraph{{Runs: runs}}, base)
+		if err != nil {
+			return nil, err
+		}
+		children, err := prepareListItems(item.Items, base)
+		if err != nil {
+			return nil, err
+		}
+		prepared = append(prepared, ListItem{
+			Runs:  paragraphs[0].Runs,
+			Items: children,
+		})
+	}
+	return prepared, nil
+}
+
+// listDepth devuelve el número de niveles de anidamiento de los elementos
+func listDepth(items []ListItem) int {
+	depth := 0
+	for _, item := range items {
+		if d := listDepth(item.Items); d > depth {
+			depth = d
+		}
+	}
+	if len(items) > 0 {
+		depth++
+	}
+	return depth
+}
+
+// parseNumbering separa un formato de numeración como "a)" en prefijo, formato y sufijo.
+// El formato es el primer "1", "a", "A", "i" o "I" que no forma parte de una palabra,
+// de modo que "Paso 1:" se interpreta como prefijo "Paso ", formato "1" y sufijo ":".
+func parseNumbering(numbering string) (prefix, format, suffix string, err error) {
+	isLetter := func(i int) bool {
+		return i >= 0 && i < len(numbering) && unicode.IsLetter(rune(numbering[i]))
+	}
+	for i := 0; i < len(numbering); i++ {
+		if !strings.ContainsRune("1aAiI", rune(numbering[i])) || isLetter(i-1) || isLetter(i+1) {
+			continue
+		}
+		return numbering[:i], numbering[i : i+1], numbering[i+1:], nil
+	}
+	return "", "", "", fmt.Errorf("formato de numeración no soportado: %q", numbering)
+}
+
+// listStyleName genera el nombre del estilo de lista de una lista
+func listStyleName(scope string, zIndex int) string {
+	return fmt.Sprintf("L%s_%d", scope, zIndex)
+}
+
+// listLevel son los elementos de un nivel de una lista, con los datos necesarios para
+// generar sus párrafos
+type listLevel struct {
+	Scope     string
+	ZIndex    int
+	StyleName string // Estilo de los párrafos
+	Path      string // Prefijo de la posición de los párrafos del nivel ("" en el primero)
+	Items     []ListItem
+}
+
+// newListLevel prepara un nivel de la lista para la plantilla
+func newListLevel(scope string, zIndex int, path string, items []ListItem) listLevel {
+	return listLevel{
+		Scope:     scope,
+		ZIndex:    zIndex,
+		StyleName: listParagraphStyleName(scope, zIndex),
+		Path:      path,
+		Items:     items,
+	}
+}
+
+// listParagraphStyleName genera el nombre del estilo de los párrafos de una lista.
+// Solo incluye la alineación para no anular las sangrías de los niveles.
+func listParagraphStyleName(scope string, zIndex int) string {
+	return fmt.Sprintf("P%s_%d_list", scope, zIndex)
+}
+
+// slideIndex devuelve la posición de la diapositiva en la presentación o -1 si no pertenece a ella
+func (g *ODPGenerator) slideIndex(slide *Slide) int {
+	for i := range g.Slides {
+		if &g.Slides[i] == slide {
+			return i
+		}
+	}
+	return -1
+}
+
+// scopeSlide devuelve la diapositiva de un ámbito de las plantillas, o nil si el
+// ámbito es el de una página maestra
+func (g *ODPGenerator) scopeSlide(scope string) *Slide {
+	slideIndex, err := strconv.Atoi(scope)
+	if err != nil || slideIndex < 0 || slideIndex >= len(g.Slides) {
+		return nil
+	}
+	return &g.Slides[slideIndex]
+}
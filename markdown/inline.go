@@ -0,0 +1,156 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/juaismar/goodp"
+)
+
+// inlineState es el formato activo al recorrer el texto de un párrafo
+type inlineState struct {
+	Bold   bool
+	Italic bool
+	Link   string
+}
+
+// parseInline convierte el formato en línea de Markdown (**negrita**, *cursiva*,
+// `código`, [enlaces](url) y <url>) en fragmentos de texto enriquecido. Los
+// fragmentos solo indican negrita, cursiva y la fuente del código; el resto del
+// estilo se hereda del cuadro de texto.
+func parseInline(text string) []goodp.Run {
+	var runs []goodp.Run
+	parseInlineInto(&runs, text, inlineState{})
+	return runs
+}
+
+// parseInlineInto añade a runs los fragmentos de text con el formato inicial state
+func parseInlineInto(runs *[]goodp.Run, text string, state inlineState) {
+	var buffer strings.Builder
+	flush := func() {
+		if buffer.Len() == 0 {
+			return
+		}
+		*runs = append(*runs, goodp.Run{Text: buffer.String(), Style: goodp.TextStyle{Bold: state.Bold, Italic: state.Italic}, Link: state.Link})
+		buffer.Reset()
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && unicode.IsPunct(rune(rest[1])):
+			buffer.WriteByte(rest[1])
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+			if end := strings.Index(rest[ticks:], rest[:ticks]); end >= 0 {
+				flush()
+				code := strings.TrimSpace(rest[ticks : ticks+end])
+				*runs = append(*runs, goodp.Run{Text: code, Style: goodp.TextStyle{FontFamily: codeFont, Bold: state.Bold, Italic: state.Italic}, Link: state.Link})
+				i += 2*ticks + end
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if state.Bold || strings.Contains(rest[2:], rest[:2]) {
+				flush()
+				state.Bold = !state.Bold
+				i += 2
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_':
+			// El guion bajo dentro de una palabra (snake_case) no es cursiva
+			inWord := rest[0] == '_' && i > 0 && isWordByte(text[i-1]) && len(rest) > 1 && isWordByte(rest[1])
+			if !inWord && (state.Italic || strings.Contains(rest[1:], rest[:1])) {
+				flush()
+				state.Italic = !state.Italic
+				i++
+				continue
+			}
+
+		case rest[0] == '!' && strings.HasPrefix(rest, "!["):
+			// Las imágenes dentro del texto se sustituyen por su texto alternativo
+			if label, _, length, ok := parseLink(rest[1:]); ok {
+				buffer.WriteString(label)
+				i += 1 + length
+				continue
+			}
+
+		case rest[0] == '[' && state.Link == "":
+			if label, url, length, ok := parseLink(rest); ok {
+				flush()
+				linked := state
+				// Los enlaces con esquemas no soportados se quedan como texto normal
+				if goodp.ValidLink(url) {
+					linked.Link = url
+				}
+				parseInlineInto(runs, label, linked)
+				i += length
+				continue
+			}
+
+		case rest[0] == '<' && state.Link == "":
+			if end := strings.IndexByte(rest, '>'); end > 0 {
+				url := rest[1:end]
+				if goodp.ValidLink(url) {
+					flush()
+					*runs = append(*runs, goodp.Run{Text: strings.TrimPrefix(url, "mailto:"), Style: goodp.TextStyle{Bold: state.Bold, Italic: state.Italic}, Link: url})
+					i += end + 1
+					continue
+				}
+			}
+		}
+		buffer.WriteByte(rest[0])
+		i++
+	}
+	flush()
+}
+
+// parseLink lee un enlace "[texto](url)" al principio de text y devuelve el texto,
+// la dirección y la longitud del enlace
+func parseLink(text string) (label, url string, length int, ok bool) {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if !strings.HasPrefix(text[i+1:], "(") {
+				return "", "", 0, false
+			}
+			end := strings.IndexByte(text[i+2:], ')')
+			if end < 0 {
+				return "", "", 0, false
+			}
+			target := strings.Fields(text[i+2 : i+2+end])
+			if len(target) == 0 {
+				return "", "", 0, false
+			}
+			return text[1:i], strings.Trim(target[0], "<>"), i + 3 + end, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// isWordByte indica si un byte forma parte de una palabra
+func isWordByte(b byte) bool {
+	return b >= 0x80 || b == '_' || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
+
+// plainText devuelve el texto de unos fragmentos sin formato
+func plainText(runs []goodp.Run) string {
+	var text strings.Builder
+	for _, run := range runs {
+		text.WriteString(run.Text)
+	}
+	return text.String()
+}
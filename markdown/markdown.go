@@ -0,0 +1,371 @@
+// Package markdown convierte documentos Markdown en presentaciones de goodp.
+//
+// Cada encabezado de nivel 1 o 2 empieza una diapositiva con ese título, y una
+// línea "---" separa diapositivas sin título. Los párrafos, las listas, las citas y
+// los encabezados de menor nivel se colocan uno debajo de otro: las listas como
+// listas de la presentación con sus niveles anidados y el resto en cuadros de texto.
+// Los bloques de código delimitados con ``` van en cuadros con fuente de ancho fijo y
+// las imágenes que ocupan una línea se escalan para caber en el espacio libre. Lo que
+// sigue a una línea "???" son las notas del orador de la diapositiva.
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"  // Formatos de imagen para calcular el tamaño de las imágenes
+	_ "image/jpeg" // Formatos de imagen para calcular el tamaño de las imágenes
+	_ "image/png"  // Formatos de imagen para calcular el tamaño de las imágenes
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juaismar/goodp"
+)
+
+// Estilo del contenido de las diapositivas
+const (
+	bodyFont     = "Liberation Sans"
+	codeFont     = "Liberation Mono"
+	textColor    = "#000000"
+	bodySize     = 18.0 // Tamaño del texto en puntos
+	headingSize  = 22.0 // Tamaño de los encabezados de nivel 3 o mayor
+	codeSize     = 14.0 // Tamaño de los bloques de código
+	codeFill     = "#F2F2F2"
+	bulletIndent = 0.8 // Sangría de cada nivel de las listas en cm
+)
+
+// Posición del contenido en la diapositiva, en cm. Coinciden con las de AddSlide.
+const (
+	marginX      = 2.0
+	contentTop   = 5.5 // Debajo del título
+	untitledTop  = 1.0 // En las diapositivas sin título
+	bottomMargin = 1.0
+	blockSpacing = 0.3 // Espacio entre bloques
+	textPadding  = 0.25
+	pointsPerCm  = 72 / 2.54
+	pixelsPerCm  = 96 / 2.54 // Resolución con la que se calcula el tamaño natural de las imágenes
+	minImageSize = 2.0       // Alto mínimo de una imagen aunque no quede espacio
+)
+
+// Options son las opciones de la conversión
+type Options struct {
+	AspectRatio string // goodp.AspectRatio169 (por defecto) o goodp.AspectRatio43
+	Images      fs.FS  // Archivos de los que se leen las imágenes; nil para no incluir imágenes locales
+}
+
+// Convert convierte un documento Markdown en una presentación. Las imágenes con
+// rutas relativas se leen de options.Images.
+func Convert(source []byte, options *Options) (*goodp.ODPGenerator, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	g := goodp.New()
+	if options.AspectRatio != "" {
+		if options.AspectRatio != goodp.AspectRatio169 && options.AspectRatio != goodp.AspectRatio43 {
+			return nil, fmt.Errorf("relación de aspecto no soportada: %s", options.AspectRatio)
+		}
+		g.SetSlideSize(options.AspectRatio)
+	}
+
+	c := &converter{g: g, images: options.Images}
+	for i, source := range parse(string(source)) {
+		if err := c.slide(source); err != nil {
+			return nil, fmt.Errorf("diapositiva %d: %v", i+1, err)
+		}
+	}
+	return g, nil
+}
+
+// ConvertFile convierte un archivo Markdown en una presentación de 16:9, leyendo
+// las imágenes relativas a la carpeta del archivo
+func ConvertFile(filename string) (*goodp.ODPGenerator, error) {
+	source, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Convert(source, &Options{Images: os.DirFS(filepath.Dir(filename))})
+}
+
+// converter añade las diapositivas a la presentación
+type converter struct {
+	g       *goodp.ODPGenerator
+	images  fs.FS
+	current *goodp.Slide // Diapositiva que se está añadiendo
+	top     float64      // Posición del siguiente bloque
+}
+
+// slide añade una diapositiva con sus bloques y sus notas
+func (c *converter) slide(source slideSource) error {
+	c.current = c.g.AddSlide(plainText(parseInline(source.Title)), "")
+	c.top = untitledTop
+	if source.Title != "" {
+		c.top = contentTop
+	}
+
+	for i, b := range source.Blocks {
+		if b.Kind == blockImage && !isRemote(b.Src) {
+			// La imagen deja sitio para los bloques de texto que la siguen
+			if err := c.image(b, c.textHeight(source.Blocks[i+1:])); err != nil {
+				return err
+			}
+			continue
+		}
+		if b.Kind == blockList {
+			if err := c.list(b.Items); err != nil {
+				return err
+			}
+			continue
+		}
+		paragraphs, size, code := blockText(b)
+		if err := c.textBlock(paragraphs, size, code); err != nil {
+			return err
+		}
+	}
+
+	if len(source.Notes) > 0 {
+		notes := make([]goodp.Paragraph, 0, len(source.Notes))
+		for _, text := range source.Notes {
+			notes = append(notes, goodp.Paragraph{Runs: parseInline(text)})
+		}
+		if err := c.g.SetRichNotes(c.current, notes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockText devuelve los párrafos de un bloque de texto, su tamaño de letra y si es
+// un bloque de código. Las imágenes remotas se sustituyen por un enlace con su texto
+// alternativo (las imágenes de datos solo con el texto). De las listas devuelve los
+// párrafos con los que se calcula su alto.
+func blockText(b block) (paragraphs []goodp.Paragraph, size float64, code bool) {
+	switch b.Kind {
+	case blockHeading:
+		runs := parseInline(b.Text)
+		for i := range runs {
+			runs[i].Style.Bold = true
+		}
+		return []goodp.Paragraph{{Runs: runs}}, headingSize, false
+	case blockQuote:
+		runs := parseInline(b.Text)
+		for i := range runs {
+			runs[i].Style.Italic = true
+		}
+		return []goodp.Paragraph{{Runs: runs, Props: &goodp.TextProperties{
+			HorizontalAlign: "left",
+			VerticalAlign:   "top",
+			LeftIndent:      bulletIndent,
+		}}}, bodySize, false
+	case blockList:
+		return listParagraphs(b.Items), bodySize, false
+	case blockCode:
+		return []goodp.Paragraph{{Runs: []goodp.Run{{Text: preserveSpaces(b.Text)}}}}, codeSize, true
+	case blockImage:
+		alt := b.Alt
+		if alt == "" {
+			alt = b.Src
+		}
+		run := goodp.Run{Text: alt}
+		if goodp.ValidLink(b.Src) {
+			run.Link = b.Src
+		}
+		return []goodp.Paragraph{{Runs: []goodp.Run{run}}}, bodySize, false
+	}
+	return []goodp.Paragraph{{Runs: parseInline(b.Text)}}, bodySize, false
+}
+
+// isRemote indica si la ruta de una imagen es una dirección web o de datos
+func isRemote(src string) bool {
+	return strings.Contains(src, "://") || strings.HasPrefix(src, "data:")
+}
+
+// textHeight devuelve el alto que ocupan los bloques de texto indicados, con la
+// separación entre ellos
+func (c *converter) textHeight(blocks []block) float64 {
+	width := c.g.SlideSize.Width - 2*marginX
+	height := 0.0
+	for _, b := range blocks {
+		if b.Kind == blockImage && !isRemote(b.Src) {
+			continue
+		}
+		paragraphs, size, code := blockText(b)
+		height += textHeight(paragraphs, width, size, code) + blockSpacing
+	}
+	return height
+}
+
+// textBlock añade un cuadro de texto debajo del bloque anterior con el alto que
+// ocupan sus párrafos. Los bloques de código usan una fuente de ancho fijo sobre un
+// rectángulo gris.
+func (c *converter) textBlock(paragraphs []goodp.Paragraph, size float64, code bool) error {
+	font := bodyFont
+	if code {
+		font = codeFont
+	}
+	width := c.g.SlideSize.Width - 2*marginX
+	height := textHeight(paragraphs, width, size, code)
+
+	if code {
+		if _, err := c.g.AddRectangle(c.current, marginX, c.top, width, height, &goodp.ShapeStyle{FillColor: codeFill}); err != nil {
+			return err
+		}
+	}
+	c.g.SetTextStyle(c.current, size, font, textColor, false, false)
+	if _, err := c.g.AddRichTextBox(c.current, paragraphs, marginX, c.top, width, height, nil); err != nil {
+		return err
+	}
+	c.top += height + blockSpacing
+	return nil
+}
+
+// image añade una imagen con su tamaño natural, reducida para caber en el ancho del
+// contenido y en el alto que queda libre dejando reserved cm para los bloques
+// siguientes, y centrada horizontalmente
+func (c *converter) image(b block, reserved float64) error {
+	if c.images == nil {
+		return fmt.Errorf("no se puede leer la imagen %q: no se indicó de dónde leer las imágenes", b.Src)
+	}
+	name := path.Clean(strings.TrimPrefix(b.Src, "./"))
+	data, err := fs.ReadFile(c.images, name)
+	if err != nil {
+		return fmt.Errorf("no se puede leer la imagen %q: %v", b.Src, err)
+	}
+
+	maxWidth := c.g.SlideSize.Width - 2*marginX
+	maxHeight := math.Max(minImageSize, c.g.SlideSize.Height-bottomMargin-c.top-reserved)
+	width, height := maxWidth, maxHeight
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil && config.Width > 0 && config.Height > 0 {
+		width, height = float64(config.Width)/pixelsPerCm, float64(config.Height)/pixelsPerCm
+		scale := math.Min(1, math.Min(maxWidth/width, maxHeight/height))
+		width, height = width*scale, height*scale
+	} else {
+		// Sin poder leer su tamaño (SVG, BMP) la imagen ocupa un cuadrado
+		width = math.Min(maxWidth, maxHeight)
+		height = width
+	}
+
+	// Si no queda sitio, la imagen se coloca pegada al borde inferior
+	y := math.Min(c.top, c.g.SlideSize.Height-bottomMargin-height)
+	x := (c.g.SlideSize.Width - width) / 2
+	if _, err := c.g.AddImage(c.current, data, path.Ext(name), x, y, width, height); err != nil {
+		return fmt.Errorf("imagen %q: %v", b.Src, err)
+	}
+	c.top = y + height + blockSpacing
+	return nil
+}
+
+// list añade una lista con viñetas o numerada debajo del bloque anterior con el alto
+// que ocupan sus elementos
+func (c *converter) list(items []listItem) error {
+	width := c.g.SlideSize.Width - 2*marginX
+	height := textHeight(listParagraphs(items), width, bodySize, false)
+
+	list := goodp.List{
+		Ordered: items[0].Ordered,
+		Items:   listTree(items),
+		Levels:  listLevels(items),
+	}
+	c.g.SetTextStyle(c.current, bodySize, bodyFont, textColor, false, false)
+	if _, err := c.g.AddList(c.current, list, marginX, c.top, width, height); err != nil {
+		return err
+	}
+	c.top += height + blockSpacing
+	return nil
+}
+
+// listTree anida los elementos de una lista según su nivel
+func listTree(items []listItem) []goodp.ListItem {
+	var tree []goodp.ListItem
+	for i := 0; i < len(items); {
+		// Los elementos de más nivel que siguen a uno son sus subelementos
+		end := i + 1
+		for end < len(items) && items[end].Level > items[i].Level {
+			end++
+		}
+		tree = append(tree, goodp.ListItem{
+			Runs:  parseInline(items[i].Text),
+			Items: listTree(items[i+1 : end]),
+		})
+		i = end
+	}
+	return tree
+}
+
+// listLevels devuelve el formato de cada nivel de una lista que mezcla niveles con
+// viñetas y numerados, según el primer elemento de cada nivel. Si todos son del
+// mismo tipo devuelve nil y la lista usa los formatos por defecto.
+func listLevels(items []listItem) []goodp.ListLevel {
+	var ordered []bool // Si cada nivel es numerado
+	mixed := false
+	for _, item := range items {
+		if item.Level == len(ordered) {
+			ordered = append(ordered, item.Ordered)
+			mixed = mixed || item.Ordered != items[0].Ordered
+		}
+	}
+	if !mixed {
+		return nil
+	}
+
+	levels := make([]goodp.ListLevel, len(ordered))
+	for i := range levels {
+		if ordered[i] {
+			levels[i].Numbering = "1."
+		}
+	}
+	return levels
+}
+
+// listParagraphs devuelve un párrafo por elemento de la lista, con la sangría de su
+// nivel, para calcular el alto que ocupa
+func listParagraphs(items []listItem) []goodp.Paragraph {
+	paragraphs := make([]goodp.Paragraph, 0, len(items))
+	for _, item := range items {
+		paragraphs = append(paragraphs, goodp.Paragraph{
+			Runs:  parseInline(item.Text),
+			Props: &goodp.TextProperties{LeftIndent: bulletIndent * float64(item.Level+1)},
+		})
+	}
+	return paragraphs
+}
+
+// preserveSpaces sustituye los espacios seguidos y los del principio de las líneas
+// por espacios de no separación, que las presentaciones no agrupan
+func preserveSpaces(code string) string {
+	var text strings.Builder
+	previous := '\n'
+	for _, c := range strings.ReplaceAll(code, "\t", "    ") {
+		if c == ' ' && (previous == ' ' || previous == '\n' || previous == '\u00a0') {
+			c = '\u00a0'
+		}
+		text.WriteRune(c)
+		previous = c
+	}
+	return text.String()
+}
+
+// textHeight estima el alto en cm que ocupan unos párrafos en un cuadro del ancho
+// indicado, suponiendo un ancho medio de carácter de medio tamaño de fuente (el
+// de las fuentes de ancho fijo es algo mayor)
+func textHeight(paragraphs []goodp.Paragraph, width, size float64, monospace bool) float64 {
+	charWidth := 0.5 * size / pointsPerCm
+	if monospace {
+		charWidth = 0.6 * size / pointsPerCm
+	}
+	lines := 0
+	for _, paragraph := range paragraphs {
+		available := width - 2*textPadding
+		if paragraph.Props != nil {
+			available -= paragraph.Props.LeftIndent
+		}
+		perLine := math.Max(1, math.Floor(available/charWidth))
+		for _, line := range strings.Split(plainText(paragraph.Runs), "\n") {
+			lines += int(math.Max(1, math.Ceil(float64(len([]rune(line)))/perLine)))
+		}
+	}
+	return float64(lines)*size*1.17/pointsPerCm + 2*textPadding
+}
@@ -0,0 +1,275 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+// blockKind es el tipo de un bloque del documento Markdown
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading             // Encabezados de nivel 3 o mayor, dentro de una diapositiva
+	blockList
+	blockCode
+	blockQuote
+	blockImage
+)
+
+// block es un bloque de contenido de una diapositiva
+type block struct {
+	Kind  blockKind
+	Text  string     // Texto de los párrafos, encabezados y citas, o código sin procesar
+	Items []listItem // Elementos de las listas
+	Alt   string     // Texto alternativo de las imágenes
+	Src   string     // Ruta de las imágenes
+}
+
+// listItem es un elemento de una lista
+type listItem struct {
+	Level   int // Nivel de anidamiento, empezando en 0
+	Ordered bool
+	Text    string
+}
+
+// slideSource es el contenido de una diapositiva antes de convertirla
+type slideSource struct {
+	Title  string
+	Blocks []block
+	Notes  []string // Párrafos de las notas del orador
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	breakPattern   = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fencePattern   = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})")
+	listPattern    = regexp.MustCompile(`^([ \t]*)([-*+]|\d{1,9}[.)])[ \t]+(.*)$`)
+	imagePattern   = regexp.MustCompile(`^!\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)$`)
+)
+
+// parse divide un documento Markdown en diapositivas. Los encabezados de nivel 1 y
+// 2 empiezan una diapositiva nueva (salvo que la actual aún esté vacía), "---"
+// separa diapositivas y "???" empieza las notas del orador de la diapositiva.
+func parse(source string) []slideSource {
+	lines := strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(source), "\n")
+
+	var slides []slideSource
+	current := slideSource{}
+	inNotes := false
+	var paragraph, notes []string
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		current.Blocks = append(current.Blocks, block{Kind: blockParagraph, Text: joinLines(paragraph)})
+		paragraph = nil
+	}
+	flushNotes := func() {
+		if len(notes) > 0 {
+			current.Notes = append(current.Notes, joinLines(notes))
+			notes = nil
+		}
+	}
+	newSlide := func() {
+		flushParagraph()
+		flushNotes()
+		if current.Title != "" || len(current.Blocks) > 0 || len(current.Notes) > 0 {
+			slides = append(slides, current)
+		}
+		current = slideSource{}
+		inNotes = false
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if breakPattern.MatchString(line) {
+			newSlide()
+			continue
+		}
+		if match := headingPattern.FindStringSubmatch(line); match != nil && len(match[1]) <= 2 {
+			if current.Title != "" || len(current.Blocks) > 0 || len(paragraph) > 0 || inNotes {
+				newSlide()
+			}
+			current.Title = match[2]
+			continue
+		}
+
+		// Las notas siguen hasta la siguiente diapositiva
+		if inNotes {
+			if trimmed == "" {
+				flushNotes()
+			} else {
+				notes = append(notes, line)
+			}
+			continue
+		}
+		if trimmed == "???" {
+			flushParagraph()
+			inNotes = true
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+
+		case headingPattern.MatchString(line):
+			flushParagraph()
+			match := headingPattern.FindStringSubmatch(line)
+			current.Blocks = append(current.Blocks, block{Kind: blockHeading, Text: match[2]})
+
+		case fencePattern.MatchString(line):
+			flushParagraph()
+			var code block
+			code, i = parseFence(lines, i)
+			current.Blocks = append(current.Blocks, code)
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			var quote []string
+			for ; i < len(lines); i++ {
+				text := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(text, ">") {
+					break
+				}
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(text, ">"), " "))
+			}
+			i--
+			current.Blocks = append(current.Blocks, block{Kind: blockQuote, Text: joinLines(quote)})
+
+		case listPattern.MatchString(line) && len(paragraph) == 0:
+			var list block
+			list, i = parseList(lines, i)
+			current.Blocks = append(current.Blocks, list)
+
+		case imagePattern.MatchString(trimmed) && len(paragraph) == 0:
+			match := imagePattern.FindStringSubmatch(trimmed)
+			current.Blocks = append(current.Blocks, block{Kind: blockImage, Alt: match[1], Src: match[2]})
+
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	newSlide()
+	return slides
+}
+
+// parseFence lee un bloque de código delimitado que empieza en la línea start y
+// devuelve el bloque y la línea en la que termina
+func parseFence(lines []string, start int) (block, int) {
+	match := fencePattern.FindStringSubmatch(lines[start])
+	indent, fence := len(match[1]), match[2]
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			break
+		}
+		// Se quita la sangría que tenía la línea de apertura
+		line := lines[i]
+		for n := 0; n < indent && strings.HasPrefix(line, " "); n++ {
+			line = line[1:]
+		}
+		code = append(code, line)
+	}
+	return block{Kind: blockCode, Text: strings.Join(code, "\n")}, i
+}
+
+// parseList lee una lista que empieza en la línea start y devuelve la lista y la
+// última línea que forma parte de ella. Un elemento del primer nivel que cambia de
+// viñetas a números, o al revés, termina la lista y empieza otra.
+func parseList(lines []string, start int) (block, int) {
+	list := block{Kind: blockList}
+	var indents []int // Sangría de cada nivel abierto
+	last := start
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			// Una línea en blanco solo continúa la lista si después sigue un elemento
+			next := i + 1
+			for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+				next++
+			}
+			if next >= len(lines) || !listPattern.MatchString(lines[next]) {
+				break
+			}
+			continue
+		}
+
+		match := listPattern.FindStringSubmatch(line)
+		if match == nil || breakPattern.MatchString(line) {
+			// Las líneas sangradas continúan el elemento anterior
+			if len(list.Items) == 0 || indentWidth(line) == 0 {
+				break
+			}
+			item := &list.Items[len(list.Items)-1]
+			item.Text = joinLines([]string{item.Text, line})
+			last = i
+			continue
+		}
+
+		indent := indentWidth(match[1])
+		for len(indents) > 0 && indent < indents[len(indents)-1] {
+			indents = indents[:len(indents)-1]
+		}
+		if len(indents) == 0 || indent > indents[len(indents)-1] {
+			indents = append(indents, indent)
+		}
+
+		item := listItem{Level: len(indents) - 1, Text: match[3]}
+		if marker := match[2]; marker[0] >= '0' && marker[0] <= '9' {
+			item.Ordered = true
+		}
+		if item.Level == 0 && len(list.Items) > 0 && item.Ordered != list.Items[0].Ordered {
+			break
+		}
+		list.Items = append(list.Items, item)
+		last = i
+	}
+	return list, last
+}
+
+// indentWidth devuelve el ancho de la sangría de una línea, contando los
+// tabuladores como cuatro espacios
+func indentWidth(line string) int {
+	width := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// joinLines une las líneas de un párrafo. Los saltos de línea son espacios salvo
+// cuando la línea termina con dos espacios o con una barra invertida.
+func joinLines(lines []string) string {
+	var text strings.Builder
+	for i, line := range lines {
+		hardBreak := strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\")
+		line = strings.TrimSpace(line)
+		if hardBreak {
+			line = strings.TrimSuffix(line, "\\")
+		}
+		text.WriteString(line)
+		if i < len(lines)-1 {
+			if hardBreak {
+				text.WriteString("\n")
+			} else {
+				text.WriteString(" ")
+			}
+		}
+	}
+	return text.String()
+}
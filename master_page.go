@@ -0,0 +1,329 @@
+package goodp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PlaceholderClass indica el tipo de contenido de un marcador de posición de una página maestra
+type PlaceholderClass string
+
+const (
+	PlaceholderTitle      PlaceholderClass = "title"
+	PlaceholderSubtitle   PlaceholderClass = "subtitle"
+	PlaceholderOutline    PlaceholderClass = "outline"
+	PlaceholderGraphic    PlaceholderClass = "graphic"
+	PlaceholderHeader     PlaceholderClass = "header"
+	PlaceholderFooter     PlaceholderClass = "footer"
+	PlaceholderDateTime   PlaceholderClass = "date-time"
+	PlaceholderPageNumber PlaceholderClass = "page-number"
+)
+
+// MasterPage representa una página maestra: la decoración común (fondo, logotipos,
+// textos fijos y campos) que comparten todas las diapositivas que la usan
+type MasterPage struct {
+	Name         string
+	Background   *Background
+	TextBoxes    []*TextBox
+	Images       []*Image
+	Placeholders []Placeholder
+	index        int
+	currentStyle TextStyle
+	lastZIndex   int
+}
+
+// Placeholder es un marco de la página maestra que define la posición del título,
+// el contenido, el pie de página, etc. en las diapositivas
+type Placeholder struct {
+	Class  PlaceholderClass
+	X      string
+	Y      string
+	Width  string
+	Height string
+}
+
+// slideNumberField es el campo de texto que muestra el número de la diapositiva
+const slideNumberField = `<text:page-number>&lt;número&gt;</text:page-number>`
+
+// AddMasterPage registra una nueva página maestra y devuelve un puntero a ella.
+// Las diapositivas la usan con Slide.SetMaster o SetDefaultMasterPage.
+func (g *ODPGenerator) AddMasterPage(name string) (*MasterPage, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("el nombre de la página maestra no puede estar vacío")
+	}
+	for _, master := range g.MasterPages {
+		if master.Name == name {
+			return nil, fmt.Errorf("la página maestra %q ya existe", name)
+		}
+	}
+	if g.template != nil {
+		for _, master := range g.template.masterPages {
+			if master == name {
+				return nil, fmt.Errorf("la página maestra %q ya existe en la plantilla", name)
+			}
+		}
+	}
+
+	master := &MasterPage{
+		Name:  name,
+		index: len(g.MasterPages),
+	}
+	g.MasterPages = append(g.MasterPages, master)
+	return master, nil
+}
+
+// SetMaster establece la página maestra que usa la diapositiva
+func (s *Slide) SetMaster(name string) {
+	s.MasterPage = name
+}
+
+// SetBackgroundColor establece un color de fondo (#RRGGBB) para la página maestra
+func (m *MasterPage) SetBackgroundColor(color string) error {
+	color = strings.TrimSpace(color)
+	if !strings.HasPrefix(color, "#") {
+		color = "#" + color
+	}
+	if !isHexColor(color) {
+		return fmt.Errorf("formato de color inválido: debe ser #RRGGBB")
+	}
+
+	m.Background = &Background{
+		Type:  BackgroundColor,
+		Color: color,
+	}
+	return nil
+}
+
+// SetBackgroundImage establece una imagen de fondo para la página maestra.
+// El parámetro extension debe incluir el punto (por ejemplo: ".jpg", ".png")
+func (m *MasterPage) SetBackgroundImage(imageData []byte, extension string) error {
+	extension, err := normalizeImageExtension(extension)
+	if err != nil {
+		return err
+	}
+	if len(imageData) == 0 {
+		return fmt.Errorf("los datos de la imagen están vacíos")
+	}
+
+	m.Background = &Background{
+		Type: BackgroundImage,
+		Data: imageData,
+		Name: fmt.Sprintf("media/master%d_background%s", m.index, extension),
+	}
+	return nil
+}
+
+// SetTextStyle establece el estilo para el próximo texto que se añada a la página maestra
+func (m *MasterPage) SetTextStyle(fontSize float64, fontFamily, color string, bold, italic bool) {
+	m.currentStyle = TextStyle{
+		FontSize:   fmt.Sprintf("%.2fpt", fontSize),
+		FontFamily: fontFamily,
+		Color:      color,
+		Bold:       bold,
+		Italic:     italic,
+	}
+}
+
+// AddTextBox añade un texto fijo a la página maestra, visible en todas sus
+// diapositivas, y devuelve un puntero a él
+func (m *MasterPage) AddTextBox(content string, x, y, width, height float64, props *TextProperties, zIndex ...int) *TextBox {
+	return m.addTextBox(escapeXML(content), x, y, width, height, props, zIndex...)
+}
+
+// AddSlideNumber añade un campo con el número de diapositiva a la página maestra y
+// devuelve un puntero a él
+func (m *MasterPage) AddSlideNumber(x, y, width, height float64, props *TextProperties, zIndex ...int) *TextBox {
+	return m.addTextBox(slideNumberField, x, y, width, height, props, zIndex...)
+}
+
+func (m *MasterPage) addTextBox(content string, x, y, width, height float64, props *TextProperties, zIndex ...int) *TextBox {
+	if props == nil {
+		props = NewDefaultTextProperties()
+	}
+
+	textBox := &TextBox{
+		Content: content,
+		X:       fmt.Sprintf("%.2fcm", x),
+		Y:       fmt.Sprintf("%.2fcm", y),
+		Width:   fmt.Sprintf("%.2fcm", width),
+		Height:  fmt.Sprintf("%.2fcm", height),
+		Style:   m.currentStyle,
+		Props:   props,
+		ZIndex:  m.getNextZIndex(zIndex...),
+	}
+	m.TextBoxes = append(m.TextBoxes, textBox)
+	return textBox
+}
+
+// AddImage añade una imagen (por ejemplo, un logotipo) a la página maestra y devuelve
+// un puntero a ella.
+// El parámetro extension debe incluir el punto (por ejemplo: ".jpg", ".png")
+func (m *MasterPage) AddImage(imageData []byte, extension string, x, y, width, height float64, zIndex ...int) (*Image, error) {
+	extension, err := normalizeImageExtension(extension)
+	if err != nil {
+		return nil, err
+	}
+	if len(imageData) == 0 {
+		return nil, fmt.Errorf("los datos de la imagen están vacíos")
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("las dimensiones de la imagen deben ser positivas")
+	}
+
+	image := &Image{
+		Data:   imageData,
+		X:      fmt.Sprintf("%.2fcm", x),
+		Y:      fmt.Sprintf("%.2fcm", y),
+		Width:  fmt.Sprintf("%.2fcm", width),
+		Height: fmt.Sprintf("%.2fcm", height),
+		Name:   fmt.Sprintf("Pictures/master%d_image%d%s", m.index, len(m.Images), extension),
+		ZIndex: m.getNextZIndex(zIndex...),
+	}
+	m.Images = append(m.Images, image)
+	return image, nil
+}
+
+// AddPlaceholder añade un marcador de posición a la página maestra
+func (m *MasterPage) AddPlaceholder(class PlaceholderClass, x, y, width, height float64) {
+	m.Placeholders = append(m.Placeholders, Placeholder{
+		Class:  class,
+		X:      fmt.Sprintf("%.2fcm", x),
+		Y:      fmt.Sprintf("%.2fcm", y),
+		Width:  fmt.Sprintf("%.2fcm", width),
+		Height: fmt.Sprintf("%.2fcm", height),
+	})
+}
+
+// getNextZIndex devuelve el siguiente Z-index de la página maestra
+func (m *MasterPage) getNextZIndex(customZIndex ...int) int {
+	nextZ := m.lastZIndex + 1
+	if len(customZIndex) > 0 {
+		nextZ = customZIndex[0]
+	}
+	m.lastZIndex = nextZ
+	return nextZ
+}
+
+// SortedElements devuelve los elementos de la página maestra ordenados por Z-index
+func (m *MasterPage) SortedElements() []DrawableElement {
+	slide := Slide{TextBoxes: m.TextBoxes, Images: m.Images}
+	return slide.SortedElements()
+}
+
+// slideMaster devuelve la página maestra registrada que usa una diapositiva, o nil
+func (g *ODPGenerator) slideMaster(slide Slide) *MasterPage {
+	if slide.MasterPage == "" {
+		return nil
+	}
+	return g.registeredMaster(slide.MasterPage)
+}
+
+// slideBackground devuelve el fondo con el que se ve una diapositiva: el suyo, el de su
+// página maestra o el de la presentación
+func (g *ODPGenerator) slideBackground(slide Slide) *Background {
+	if slide.Background != nil {
+		return slide.Background
+	}
+	if master := g.slideMaster(slide); master != nil && master.Background != nil {
+		return master.Background
+	}
+	return g.Background
+}
+
+// registeredMaster busca una página maestra registrada con AddMasterPage
+func (g *ODPGenerator) registeredMaster(name string) *MasterPage {
+	for _, master := range g.MasterPages {
+		if master.Name == name {
+			return master
+		}
+	}
+	return nil
+}
+
+// pageStyleName devuelve el estilo de página (drawing-page) de una diapositiva.
+// Si la página maestra tiene su propio fondo no se aplica el fondo global. Las
+// diapositivas con transición o con pie de página propio usan su propio estilo,
+// que incluye también el fondo.
+func (g *ODPGenerator) pageStyleName(index int, slide Slide) string {
+	master := g.registeredMaster(g.masterPageName(slide))
+	switch {
+	case g.hasOwnPageStyle(slide):
+		return fmt.Sprintf("slidePage%d", index)
+	case slide.Background != nil:
+		return fmt.Sprintf("slideBackground%d", index)
+	case master != nil && master.Background != nil:
+		return "dp1"
+	case g.Background != nil:
+		return "backgroundStyle"
+	default:
+		return "dp1"
+	}
+}
+
+// hasOwnPageStyle indica si la diapositiva necesita un estilo de página propio
+func (g *ODPGenerator) hasOwnPageStyle(slide Slide) bool {
+	return g.slideTransition(slide) != nil || slide.HeaderFooter != nil
+}
+
+// pageStyle es un estilo de página (drawing-page) propio de una diapositiva
+type pageStyle struct {
+	Name         string
+	Background   *Background
+	FillImage    string // Nombre del relleno de la imagen de fondo
+	Transition   *Transition
+	HeaderFooter *HeaderFooter
+}
+
+// slidePageStyles devuelve los estilos de página de las diapositivas con transición
+// o con pie de página propio, que incluyen también el fondo que les corresponde
+func (g *ODPGenerator) slidePageStyles() []pageStyle {
+	var styles []pageStyle
+	for i, slide := range g.Slides {
+		if !g.hasOwnPageStyle(slide) {
+			continue
+		}
+
+		style := pageStyle{
+			Name:         fmt.Sprintf("slidePage%d", i),
+			Transition:   g.slideTransition(slide),
+			HeaderFooter: g.slideHeaderFooter(slide),
+		}
+		master := g.registeredMaster(g.masterPageName(slide))
+		switch {
+		case slide.Background != nil:
+			style.Background = slide.Background
+			style.FillImage = fmt.Sprintf("slideBackground%d", i)
+		case master != nil && master.Background != nil:
+			// Se muestra el fondo de la página maestra
+		case g.Background != nil:
+			style.Background = g.Background
+			style.FillImage = "backgroundImage"
+		}
+		styles = append(styles, style)
+	}
+	return styles
+}
+
+// validateMasterPages comprueba que todas las diapositivas usan páginas maestras existentes
+func (g *ODPGenerator) validateMasterPages() error {
+	for i, slide := range g.Slides {
+		if slide.MasterPage != "" && !g.hasMasterPage(slide.MasterPage) {
+			return fmt.Errorf("la diapositiva %d usa la página maestra %q, que no existe", i, slide.MasterPage)
+		}
+	}
+	return nil
+}
+
+// isHexColor indica si color tiene el formato #RRGGBB
+func isHexColor(color string) bool {
+	if len(color) != 7 || color[0] != '#' {
+		return false
+	}
+	for _, c := range color[1:] {
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
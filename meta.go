@@ -0,0 +1,225 @@
+package goodp
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// metaGenerator es el programa que se indica como generador en meta.xml
+const metaGenerator = "goodp"
+
+// metaDateLayout es el formato de las fechas de meta.xml
+const metaDateLayout = "2006-01-02T15:04:05"
+
+// Metadata son las propiedades del documento que se guardan en meta.xml y que
+// muestran los gestores de archivos y el diálogo de propiedades de Impress
+type Metadata struct {
+	Title       string
+	Subject     string
+	Description string
+	Creator     string // Autor de la presentación
+	Language    string // Idioma, por ejemplo "es-ES"
+	Keywords    []string
+	Created     time.Time // Fecha de creación (New la inicializa con la fecha actual)
+	Modified    time.Time // Fecha de modificación (vacía para usar la fecha al guardar)
+	UserDefined []UserProperty
+}
+
+// UserProperty es una propiedad personalizada del documento. Value puede ser un
+// string, un bool, un número, un time.Time o un time.Duration.
+type UserProperty struct {
+	Name  string
+	Value interface{}
+}
+
+// validateMetadata comprueba que las propiedades personalizadas tienen nombre y un tipo soportado
+func (g *ODPGenerator) validateMetadata() error {
+	seen := make(map[string]bool)
+	for _, property := range g.Metadata.UserDefined {
+		if strings.TrimSpace(property.Name) == "" {
+			return fmt.Errorf("las propiedades personalizadas del documento deben tener nombre")
+		}
+		if seen[property.Name] {
+			return fmt.Errorf("la propiedad personalizada %q está repetida", property.Name)
+		}
+		seen[property.Name] = true
+		if _, _, ok := userPropertyValue(property.Value); !ok {
+			return fmt.Errorf("tipo no soportado en la propiedad personalizada %q: %T", property.Name, property.Value)
+		}
+	}
+	return nil
+}
+
+// userPropertyValue devuelve el tipo de valor de OpenDocument y el texto de una
+// propiedad personalizada
+func userPropertyValue(value interface{}) (valueType, text string, ok bool) {
+	switch v := value.(type) {
+	case string:
+		return "string", v, true
+	case bool:
+		return "boolean", strconv.FormatBool(v), true
+	case int:
+		return "float", strconv.Itoa(v), true
+	case int64:
+		return "float", strconv.FormatInt(v, 10), true
+	case float32:
+		return "float", strconv.FormatFloat(float64(v), 'g', -1, 32), true
+	case float64:
+		return "float", strconv.FormatFloat(v, 'g', -1, 64), true
+	case time.Time:
+		return "date", v.Format(metaDateLayout), true
+	case time.Duration:
+		return "time", formatISODuration(v), true
+	}
+	return "", "", false
+}
+
+// formatISODuration da formato ISO 8601 ("PT1H30M0S") a una duración
+func formatISODuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	hours := int64(d / time.Hour)
+	minutes := int64(d % time.Hour / time.Minute)
+	seconds := (d % time.Minute).Seconds()
+	return fmt.Sprintf("%sPT%dH%dM%sS", sign, hours, minutes, strconv.FormatFloat(seconds, 'f', -1, 64))
+}
+
+// writeMeta genera meta.xml con las propiedades del documento
+func (g *ODPGenerator) writeMeta(writer io.Writer) error {
+	metaTemplate := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-meta xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+                      xmlns:meta="urn:oasis:names:tc:opendocument:xmlns:meta:1.0"
+                      xmlns:dc="http://purl.org/dc/elements/1.1/"
+                      xmlns:xlink="http://www.w3.org/1999/xlink"
+                      office:version="1.2">
+    <office:meta>
+        <meta:generator>{{generator}}</meta:generator>
+        {{with .Title}}<dc:title>{{html .}}</dc:title>{{end}}
+        {{with .Subject}}<dc:subject>{{html .}}</dc:subject>{{end}}
+        {{with .Description}}<dc:description>{{html .}}</dc:description>{{end}}
+        {{with .Creator}}
+        <meta:initial-creator>{{html .}}</meta:initial-creator>
+        <dc:creator>{{html .}}</dc:creator>
+        {{end}}
+        {{if not .Created.IsZero}}<meta:creation-date>{{date .Created}}</meta:creation-date>{{end}}
+        <dc:date>{{modified}}</dc:date>
+        {{with .Language}}<dc:language>{{html .}}</dc:language>{{end}}
+        {{range .Keywords}}
+        <meta:keyword>{{html .}}</meta:keyword>
+        {{end}}
+        {{range .UserDefined}}
+        {{$value := userValue .Value}}
+        <meta:user-defined meta:name="{{html .Name}}" meta:value-type="{{$value.Type}}">{{html $value.Text}}</meta:user-defined>
+        {{end}}
+    </office:meta>
+</office:document-meta>`
+
+	tmpl, err := template.New("meta").Funcs(template.FuncMap{
+		"generator": func() string {
+			return metaGenerator
+		},
+		"date": func(t time.Time) string {
+			return t.Format(metaDateLayout)
+		},
+		"modified": func() string {
+			if g.Metadata.Modified.IsZero() {
+				return time.Now().Format(metaDateLayout)
+			}
+			return g.Metadata.Modified.Format(metaDateLayout)
+		},
+		"userValue": func(value interface{}) map[string]string {
+			valueType, text, _ := userPropertyValue(value)
+			return map[string]string{"Type": valueType, "Text": text}
+		},
+	}).Parse(metaTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(writer, g.Metadata)
+}
+
+// metadata lee las propiedades del documento de meta.xml, si el paquete lo incluye
+func (r *odpReader) metadata() Metadata {
+	var metadata Metadata
+	data, err := r.readFile("meta.xml")
+	if err != nil {
+		return metadata
+	}
+	root, err := parseXMLTree(data)
+	if err != nil {
+		return metadata
+	}
+	meta := root.find(nsOffice, "meta")
+	if meta == nil {
+		return metadata
+	}
+
+	text := func(space, local string) string {
+		if node := meta.child(space, local); node != nil {
+			return node.text()
+		}
+		return ""
+	}
+	metadata.Title = text(nsDC, "title")
+	metadata.Subject = text(nsDC, "subject")
+	metadata.Description = text(nsDC, "description")
+	metadata.Language = text(nsDC, "language")
+	metadata.Creator = text(nsDC, "creator")
+	if metadata.Creator == "" {
+		metadata.Creator = text(nsMeta, "initial-creator")
+	}
+	metadata.Created, _ = parseMetaDate(text(nsMeta, "creation-date"))
+	metadata.Modified, _ = parseMetaDate(text(nsDC, "date"))
+
+	for _, keyword := range meta.children(nsMeta, "keyword") {
+		metadata.Keywords = append(metadata.Keywords, keyword.text())
+	}
+	for _, node := range meta.children(nsMeta, "user-defined") {
+		property := UserProperty{Name: node.attr(nsMeta, "name"), Value: node.text()}
+		switch node.attr(nsMeta, "value-type") {
+		case "float":
+			if value, err := strconv.ParseFloat(node.text(), 64); err == nil {
+				property.Value = value
+			}
+		case "boolean":
+			if value, err := strconv.ParseBool(node.text()); err == nil {
+				property.Value = value
+			}
+		case "date":
+			if value, err := parseMetaDate(node.text()); err == nil {
+				property.Value = value
+			}
+		case "time":
+			value := strings.TrimSpace(node.text())
+			if strings.HasPrefix(strings.TrimPrefix(value, "-"), "PT") {
+				seconds := parseDuration(strings.TrimPrefix(value, "-"))
+				if strings.HasPrefix(value, "-") {
+					seconds = -seconds
+				}
+				property.Value = time.Duration(math.Round(seconds * float64(time.Second)))
+			}
+		}
+		if property.Name != "" {
+			metadata.UserDefined = append(metadata.UserDefined, property)
+		}
+	}
+	return metadata
+}
+
+// parseMetaDate interpreta una fecha de meta.xml, con o sin zona horaria
+func parseMetaDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("fecha inválida: %q", value)
+}
@@ -0,0 +1,80 @@
+package goodp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dimensiones de la página de notas (A4 vertical), en cm
+const (
+	notesPageWidth  = 21.0
+	notesPageHeight = 29.7
+	notesMargin     = 2.0
+)
+
+// notesGeometry es la posición de la miniatura de la diapositiva y del texto en la página de notas
+type notesGeometry struct {
+	ThumbnailX      string
+	ThumbnailY      string
+	ThumbnailWidth  string
+	ThumbnailHeight string
+	TextX           string
+	TextY           string
+	TextWidth       string
+	TextHeight      string
+}
+
+// SetNotes establece las notas del orador de la diapositiva, que se muestran en la
+// consola del presentador. Cada línea de notes es un párrafo.
+func (g *ODPGenerator) SetNotes(slide *Slide, notes string) {
+	if notes == "" {
+		slide.Notes = nil
+		return
+	}
+	lines := strings.Split(notes, "\n")
+	paragraphs := make([]Paragraph, 0, len(lines))
+	for _, line := range lines {
+		paragraphs = append(paragraphs, Paragraph{Runs: []Run{{Text: line}}})
+	}
+	// El texto plano no tiene enlaces, así que no puede fallar
+	slide.Notes, _ = prepareParagraphs(paragraphs, TextStyle{})
+}
+
+// SetRichNotes establece las notas del orador de la diapositiva como texto enriquecido.
+// Devuelve un error si algún fragmento tiene un enlace inválido.
+func (g *ODPGenerator) SetRichNotes(slide *Slide, paragraphs []Paragraph) error {
+	prepared, err := prepareParagraphs(paragraphs, TextStyle{})
+	if err != nil {
+		return err
+	}
+	slide.Notes = prepared
+	return nil
+}
+
+// notesGeometry calcula la posición de la miniatura, que conserva la proporción de
+// las diapositivas, y del cuadro de texto de las notas
+func (g *ODPGenerator) notesGeometry() notesGeometry {
+	width := notesPageWidth - 2*notesMargin
+	height := width * g.SlideSize.Height / g.SlideSize.Width
+	if maxHeight := notesPageHeight/2 - 2*notesMargin; height > maxHeight {
+		height = maxHeight
+		width = height * g.SlideSize.Width / g.SlideSize.Height
+	}
+
+	textY := notesMargin + height + notesMargin
+	return notesGeometry{
+		ThumbnailX:      fmt.Sprintf("%.2fcm", (notesPageWidth-width)/2),
+		ThumbnailY:      fmt.Sprintf("%.2fcm", notesMargin),
+		ThumbnailWidth:  fmt.Sprintf("%.2fcm", width),
+		ThumbnailHeight: fmt.Sprintf("%.2fcm", height),
+		TextX:           fmt.Sprintf("%.2fcm", notesMargin),
+		TextY:           fmt.Sprintf("%.2fcm", textY),
+		TextWidth:       fmt.Sprintf("%.2fcm", notesPageWidth-2*notesMargin),
+		TextHeight:      fmt.Sprintf("%.2fcm", notesPageHeight-textY-notesMargin),
+	}
+}
+
+// notesParagraphStyleID devuelve el estilo del párrafo index de las notas de una diapositiva
+func notesParagraphStyleID(scope string, notes []Paragraph, index int) string {
+	return generateParaStyleID(scope+"_notes", index, notes[index].Props)
+}
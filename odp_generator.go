@@ -1,7 +1,6 @@
 package goodp
 
 import (
-	"archive/zip"
 	"bytes"
 	"fmt"
 	"io"
@@ -10,6 +9,7 @@ import (
 	"sort"
 	"strings"
 	"text/template"
+	"time"
 )
 
 // TODO Refactor this to use a list of sizes and override with a custom size
@@ -21,8 +21,8 @@ const (
 
 // SlideSize representa las dimensiones de la diapositiva
 type SlideSize struct {
-	Width  float64
-	Height float64
+	Width  float64 `json:"width" yaml:"width"`
+	Height float64 `json:"height" yaml:"height"`
 }
 
 // Tamaños predefinidos (en centímetros)
@@ -47,36 +47,57 @@ type Background struct {
 }
 
 type ODPGenerator struct {
-	Slides     []Slide
-	SlideSize  SlideSize
-	Background *Background
+	Slides       []Slide
+	SlideSize    SlideSize
+	Background   *Background
+	MasterPages  []*MasterPage     // Páginas maestras registradas con AddMasterPage
+	Transition   *Transition       // Transición por defecto de las diapositivas
+	HeaderFooter *HeaderFooter     // Pie de página, número y fecha por defecto de las diapositivas
+	Metadata     Metadata          // Propiedades del documento (título, autor...)
+	template     *documentTemplate // Plantilla con páginas maestras y estilos (opcional)
+	masterPage   string            // Página maestra por defecto para las nuevas diapositivas
+
+	compressionLevel int  // Nivel de compresión de flate de los archivos del paquete
+	compressionSet   bool // Si se indicó el nivel con SetCompressionLevel
 }
 
 type Slide struct {
-	TextBoxes    []TextBox
-	Images       []Image
+	TextBoxes    []*TextBox
+	Images       []*Image
+	Lists        []*List
+	Tables       []*Table
+	Shapes       []*Shape
+	Charts       []*Chart
+	Notes        []Paragraph         // Notas del orador
+	Transition   *Transition         // Transición propia (nil para usar la de la presentación)
+	Animations   []Animation         // Animaciones de los elementos, en orden de reproducción
+	ClickActions map[int]ClickAction // Acciones al hacer clic en los elementos, por Z-index
+	ID           string              // Identificador estable, usado como nombre de la página (opcional)
+	HeaderFooter *HeaderFooter       // Pie de página, número y fecha propios (nil para usar los de la presentación)
 	currentStyle TextStyle
 	Background   *Background
+	MasterPage   string // Nombre de la página maestra (vacío para usar la de por defecto)
 	lastZIndex   int
 }
 
 type TextBox struct {
-	Content string
-	X       string // Posición X en cm
-	Y       string // Posición Y en cm
-	Width   string // Ancho en cm
-	Height  string // Alto en cm
-	Style   TextStyle
-	Props   *TextProperties // Cambiado a puntero para que sea opcional
-	ZIndex  int
+	Content    string
+	Paragraphs []Paragraph // Texto enriquecido (si no está vacío, sustituye a Content)
+	X          string      // Posición X en cm
+	Y          string      // Posición Y en cm
+	Width      string      // Ancho en cm
+	Height     string      // Alto en cm
+	Style      TextStyle
+	Props      *TextProperties // Cambiado a puntero para que sea opcional
+	ZIndex     int
 }
 
 type TextProperties struct {
-	HorizontalAlign string  // "left", "center", "right", "justify"
-	VerticalAlign   string  // "top", "middle", "bottom"
-	LeftIndent      float64 // Sangría izquierda en cm
-	RightIndent     float64 // Sangría derecha en cm
-	FirstLineIndent float64 // Sangría de primera línea en cm
+	HorizontalAlign string  `json:"horizontalAlign,omitempty" yaml:"horizontalAlign,omitempty"` // "left", "center", "right", "justify"
+	VerticalAlign   string  `json:"verticalAlign,omitempty" yaml:"verticalAlign,omitempty"`     // "top", "middle", "bottom"
+	LeftIndent      float64 `json:"leftIndent,omitempty" yaml:"leftIndent,omitempty"`           // Sangría izquierda en cm
+	RightIndent     float64 `json:"rightIndent,omitempty" yaml:"rightIndent,omitempty"`         // Sangría derecha en cm
+	FirstLineIndent float64 `json:"firstLineIndent,omitempty" yaml:"firstLineIndent,omitempty"` // Sangría de primera línea en cm
 }
 
 type Image struct {
@@ -90,18 +111,18 @@ type Image struct {
 }
 
 type TextStyle struct {
-	FontSize   string
-	FontFamily string
-	Color      string
-	Bold       bool
-	Italic     bool
+	FontSize   string `json:"fontSize,omitempty" yaml:"fontSize,omitempty"`
+	FontFamily string `json:"fontFamily,omitempty" yaml:"fontFamily,omitempty"`
+	Color      string `json:"color,omitempty" yaml:"color,omitempty"`
+	Bold       bool   `json:"bold,omitempty" yaml:"bold,omitempty"`
+	Italic     bool   `json:"italic,omitempty" yaml:"italic,omitempty"`
 }
 
 // Añadir esta nueva estructura para manejar elementos ordenables
 type DrawableElement struct {
-	Type   string // "textbox" o "image"
+	Type   string // "textbox", "image", "list", "table", "shape" o "chart"
 	ZIndex int
-	Data   interface{} // TextBox o Image
+	Data   interface{} // TextBox, Image, List, Table, Shape o Chart
 }
 
 // New crea una nueva instancia de ODPGenerator con tamaño 16:9 por defecto
@@ -109,6 +130,7 @@ func New() *ODPGenerator {
 	return &ODPGenerator{
 		Slides:    make([]Slide, 0),
 		SlideSize: defaultSize169,
+		Metadata:  Metadata{Created: time.Now()},
 	}
 }
 
@@ -135,14 +157,15 @@ func (g *ODPGenerator) SetCustomSlideSize(width, height float64) {
 
 // escapeXML escapa los caracteres especiales de XML
 func escapeXML(text string) string {
-
-	text = strings.ReplaceAll(text, "\n", "<text:line-break/>")
-	return strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "<", "&lt;")
+	text = strings.ReplaceAll(text, ">", "&gt;")
+	return strings.ReplaceAll(text, "\n", "<text:line-break/>")
 }
 
 // AddSlide añade una nueva diapositiva a la presentación y devuelve un puntero a ella
 func (g *ODPGenerator) AddSlide(title string, content string) *Slide {
-	slide := &Slide{}
+	slide := &Slide{MasterPage: g.masterPage}
 
 	// Crear TextBox para el título
 	if title != "" {
@@ -155,7 +178,7 @@ func (g *ODPGenerator) AddSlide(title string, content string) *Slide {
 		}
 
 		// TextBox del título (posicionado en la parte superior)
-		g.AddTextBox(slide, title,
+		slide.addTextBox(TextBox{Content: escapeXML(title)},
 			2,                   // x: 2cm desde el borde izquierdo
 			1,                   // y: 1cm desde el borde superior
 			g.SlideSize.Width-4, // ancho: ancho total - 4cm de márgenes
@@ -176,7 +199,7 @@ func (g *ODPGenerator) AddSlide(title string, content string) *Slide {
 		}
 
 		// TextBox del contenido (debajo del título)
-		g.AddTextBox(slide, content,
+		slide.addTextBox(TextBox{Content: escapeXML(content)},
 			2,                   // x: 2cm desde el borde izquierdo
 			5.5,                 // y: 5.5cm desde el borde superior
 			g.SlideSize.Width-4, // ancho: ancho total - 4cm de márgenes
@@ -193,7 +216,7 @@ func (g *ODPGenerator) AddSlide(title string, content string) *Slide {
 
 // AddBlankSlide añade una diapositiva en blanco a la presentación y devuelve un puntero a ella
 func (g *ODPGenerator) AddBlankSlide() *Slide {
-	g.Slides = append(g.Slides, Slide{})
+	g.Slides = append(g.Slides, Slide{MasterPage: g.masterPage})
 	return &g.Slides[len(g.Slides)-1]
 }
 
@@ -229,28 +252,36 @@ func NewDefaultTextProperties() *TextProperties {
 	}
 }
 
-// Modificar AddTextBox para inicializar props si es nil
-func (g *ODPGenerator) AddTextBox(slide *Slide, content string, x, y, width, height float64, props *TextProperties, zIndex ...int) {
-	// Si props es nil, usar valores por defecto
+// Modificar AddTextBox para inicializar props si es nil. Devuelve el cuadro de texto
+// añadido, cuyo ZIndex sirve para animarlo con AddAnimation.
+func (g *ODPGenerator) AddTextBox(slide *Slide, content string, x, y, width, height float64, props *TextProperties, zIndex ...int) (*TextBox, error) {
+	if g.slideIndex(slide) == -1 {
+		return nil, fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	return slide.addTextBox(TextBox{Content: escapeXML(content)}, x, y, width, height, props, zIndex...), nil
+}
+
+// addTextBox añade a la diapositiva un cuadro de texto con el estilo actual y la
+// posición y el tamaño indicados. Si props es nil se usan los valores por defecto.
+func (s *Slide) addTextBox(textBox TextBox, x, y, width, height float64, props *TextProperties, zIndex ...int) *TextBox {
 	if props == nil {
 		props = NewDefaultTextProperties()
 	}
 
-	slide.TextBoxes = append(slide.TextBoxes, TextBox{
-		Content: escapeXML(content),
-		X:       fmt.Sprintf("%.2fcm", x),
-		Y:       fmt.Sprintf("%.2fcm", y),
-		Width:   fmt.Sprintf("%.2fcm", width),
-		Height:  fmt.Sprintf("%.2fcm", height),
-		Style:   slide.currentStyle,
-		Props:   props,
-		ZIndex:  slide.getNextZIndex(zIndex...),
-	})
+	textBox.X = fmt.Sprintf("%.2fcm", x)
+	textBox.Y = fmt.Sprintf("%.2fcm", y)
+	textBox.Width = fmt.Sprintf("%.2fcm", width)
+	textBox.Height = fmt.Sprintf("%.2fcm", height)
+	textBox.Style = s.currentStyle
+	textBox.Props = props
+	textBox.ZIndex = s.getNextZIndex(zIndex...)
+	s.TextBoxes = append(s.TextBoxes, &textBox)
+	return &textBox
 }
 
-// AddImage añade una imagen a la diapositiva especificada.
+// AddImage añade una imagen a la diapositiva especificada y devuelve un puntero a ella.
 // El parámetro extension debe incluir el punto (por ejemplo: ".jpg", ".png")
-func (g *ODPGenerator) AddImage(slide *Slide, imageData []byte, extension string, x, y, width, height float64, zIndex ...int) error {
+func (g *ODPGenerator) AddImage(slide *Slide, imageData []byte, extension string, x, y, width, height float64, zIndex ...int) (*Image, error) {
 	// Validar que el slide pertenece a esta presentación
 	slideIndex := -1
 	for i := range g.Slides {
@@ -260,42 +291,30 @@ func (g *ODPGenerator) AddImage(slide *Slide, imageData []byte, extension string
 		}
 	}
 	if slideIndex == -1 {
-		return fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+		return nil, fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
 	}
 
 	// Validar la extensión
-	extension = strings.ToLower(strings.TrimSpace(extension))
-	if !strings.HasPrefix(extension, ".") {
-		extension = "." + extension
-	}
-
-	// Validar que sea una extensión de imagen soportada
-	validExtensions := map[string]bool{
-		".jpg": true, ".jpeg": true,
-		".png": true,
-		".gif": true,
-		".bmp": true,
-		".svg": true,
-	}
-	if !validExtensions[extension] {
-		return fmt.Errorf("formato de imagen no soportado: %s", extension)
+	extension, err := normalizeImageExtension(extension)
+	if err != nil {
+		return nil, err
 	}
 
 	// Validar que imageData no esté vacío
 	if len(imageData) == 0 {
-		return fmt.Errorf("los datos de la imagen están vacíos")
+		return nil, fmt.Errorf("los datos de la imagen están vacíos")
 	}
 
 	// Validar dimensiones
 	if width <= 0 || height <= 0 {
-		return fmt.Errorf("las dimensiones de la imagen deben ser positivas")
+		return nil, fmt.Errorf("las dimensiones de la imagen deben ser positivas")
 	}
 
 	// Validar que la imagen cabe en la diapositiva
 	if x < 0 || y < 0 ||
 		x+width > g.SlideSize.Width ||
 		y+height > g.SlideSize.Height {
-		return fmt.Errorf("la imagen se sale de los límites de la diapositiva (%.2f x %.2f)",
+		return nil, fmt.Errorf("la imagen se sale de los límites de la diapositiva (%.2f x %.2f)",
 			g.SlideSize.Width, g.SlideSize.Height)
 	}
 
@@ -305,7 +324,7 @@ func (g *ODPGenerator) AddImage(slide *Slide, imageData []byte, extension string
 		len(slide.Images),
 		extension)
 
-	slide.Images = append(slide.Images, Image{
+	image := &Image{
 		Data:   imageData,
 		X:      fmt.Sprintf("%.2fcm", x),
 		Y:      fmt.Sprintf("%.2fcm", y),
@@ -313,9 +332,31 @@ func (g *ODPGenerator) AddImage(slide *Slide, imageData []byte, extension string
 		Height: fmt.Sprintf("%.2fcm", height),
 		Name:   imageName,
 		ZIndex: slide.getNextZIndex(zIndex...),
-	})
+	}
+	slide.Images = append(slide.Images, image)
+	return image, nil
+}
 
-	return nil
+// normalizeImageExtension normaliza la extensión de una imagen (en minúsculas y con
+// el punto inicial) y comprueba que sea un formato soportado
+func normalizeImageExtension(extension string) (string, error) {
+	extension = strings.ToLower(strings.TrimSpace(extension))
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+
+	// Validar que sea una extensión de imagen soportada
+	validExtensions := map[string]bool{
+		".jpg": true, ".jpeg": true,
+		".png": true,
+		".gif": true,
+		".bmp": true,
+		".svg": true,
+	}
+	if !validExtensions[extension] {
+		return "", fmt.Errorf("formato de imagen no soportado: %s", extension)
+	}
+	return extension, nil
 }
 
 // SetBackgroundImage establece una imagen de fondo para todas las diapositivas
@@ -347,21 +388,9 @@ func (g *ODPGenerator) SetSlideBackground(slide *Slide, imageData []byte, extens
 	}
 
 	// Validar la extensión
-	extension = strings.ToLower(strings.TrimSpace(extension))
-	if !strings.HasPrefix(extension, ".") {
-		extension = "." + extension
-	}
-
-	// Validar que sea una extensión de imagen soportada
-	validExtensions := map[string]bool{
-		".jpg": true, ".jpeg": true,
-		".png": true,
-		".gif": true,
-		".bmp": true,
-		".svg": true,
-	}
-	if !validExtensions[extension] {
-		return fmt.Errorf("formato de imagen no soportado: %s", extension)
+	extension, err := normalizeImageExtension(extension)
+	if err != nil {
+		return err
 	}
 
 	// Validar que imageData no esté vacío
@@ -427,136 +456,144 @@ func (g *ODPGenerator) SetSlideBackgroundColor(slide *Slide, color string) error
 	return nil
 }
 
-// SaveStream genera y devuelve los bytes del archivo ODP
-func (g *ODPGenerator) SaveStream() ([]byte, error) {
-	// Crear el archivo ZIP (ODP es un archivo ZIP)
-	buf := new(bytes.Buffer)
-	zipWriter := zip.NewWriter(buf)
-
-	// Añadir mimetype
-	mimetypeWriter, err := zipWriter.Create("mimetype")
-	if err != nil {
-		return nil, err
-	}
-	_, err = mimetypeWriter.Write([]byte("application/vnd.oasis.opendocument.presentation"))
-	if err != nil {
-		return nil, err
-	}
-
-	// Añadir content.xml
-	contentWriter, err := zipWriter.Create("content.xml")
-	if err != nil {
-		return nil, err
-	}
-	err = g.writeContent(contentWriter)
-	if err != nil {
-		return nil, err
-	}
+// countingWriter cuenta los bytes escritos en w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
 
-	// Añadir styles.xml
-	stylesWriter, err := zipWriter.Create("styles.xml")
-	if err != nil {
-		return nil, err
-	}
-	err = g.writeStyles(stylesWriter)
-	if err != nil {
-		return nil, err
-	}
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
 
-	// Añadir settings.xml
-	settingsWriter, err := zipWriter.Create("settings.xml")
-	if err != nil {
-		return nil, err
-	}
-	err = g.writeSettings(settingsWriter)
-	if err != nil {
-		return nil, err
+// WriteTo escribe el archivo ODP en w a medida que se genera, sin guardarlo entero en
+// memoria, y devuelve el número de bytes escritos. Implementa io.WriterTo, por lo que
+// sirve para enviar la presentación directamente como respuesta HTTP. La presentación
+// se valida antes de escribir nada, pero si falla la escritura w queda a medias.
+func (g *ODPGenerator) WriteTo(w io.Writer) (int64, error) {
+	if err := g.validate(); err != nil {
+		return 0, err
 	}
 
-	// Añadir configurations2/accelerator/current.xml
-	configWriter, err := zipWriter.Create("configurations2/accelerator/current.xml")
-	if err != nil {
-		return nil, err
-	}
-	err = g.writeConfigurations(configWriter)
-	if err != nil {
-		return nil, err
+	// Crear el paquete (ODP es un archivo ZIP), empezando por el mimetype
+	counter := &countingWriter{w: w}
+	pkg := newPackageWriter(counter, g.packageCompression())
+	if err := pkg.writeMimetype(); err != nil {
+		return counter.n, err
 	}
 
-	// Añadir manifest
-	manifestWriter, err := zipWriter.Create("META-INF/manifest.xml")
-	if err != nil {
-		return nil, err
+	// Añadir los documentos XML y el manifiesto
+	parts := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"content.xml", g.writeContent},
+		{"styles.xml", g.writeStyles},
+		{"settings.xml", g.writeSettings},
+		{"meta.xml", g.writeMeta},
+		{"configurations2/accelerator/current.xml", g.writeConfigurations},
+		{"META-INF/manifest.xml", g.writeManifest},
 	}
-	err = g.writeManifest(manifestWriter)
-	if err != nil {
-		return nil, err
-	}
-
-	// Añadir la imagen de fondo global si existe y es una imagen
-	if g.Background != nil && g.Background.Type == BackgroundImage {
-		imageWriter, err := zipWriter.Create(g.Background.Name)
+	for _, part := range parts {
+		partWriter, err := pkg.create(part.name)
 		if err != nil {
-			return nil, err
+			return counter.n, err
 		}
-		_, err = imageWriter.Write(g.Background.Data)
-		if err != nil {
-			return nil, err
+		if err := part.write(partWriter); err != nil {
+			return counter.n, err
 		}
 	}
 
-	// Añadir las imágenes de fondo por diapositiva
-	for _, slide := range g.Slides {
-		if slide.Background != nil && slide.Background.Type == BackgroundImage {
-			imageWriter, err := zipWriter.Create(slide.Background.Name)
-			if err != nil {
-				return nil, err
-			}
-			_, err = imageWriter.Write(slide.Background.Data)
-			if err != nil {
-				return nil, err
-			}
+	// Añadir la miniatura de la primera diapositiva
+	if len(g.Slides) > 0 {
+		thumbnail, err := g.thumbnail()
+		if err != nil {
+			return counter.n, err
+		}
+		if err := pkg.writeFile("Thumbnails/thumbnail.png", "image/png", thumbnail); err != nil {
+			return counter.n, err
 		}
 	}
 
-	// Añadir las imágenes al archivo ZIP
-	for _, slide := range g.Slides {
-		for _, img := range slide.Images {
-			imageWriter, err := zipWriter.Create(img.Name)
-			if err != nil {
-				return nil, err
-			}
-
-			_, err = imageWriter.Write(img.Data)
-			if err != nil {
-				return nil, err
-			}
+	// Añadir las imágenes (fondos e imágenes de las diapositivas) y los gráficos al archivo ZIP
+	for _, file := range g.packageFiles() {
+		// Los directorios de los subdocumentos solo aparecen en el manifiesto
+		if strings.HasSuffix(file.Name, "/") {
+			continue
+		}
+		if err := pkg.writeFile(file.Name, file.MediaType, file.Data); err != nil {
+			return counter.n, err
 		}
 	}
 
 	// Cerrar el ZIP
-	err = zipWriter.Close()
-	if err != nil {
+	err := pkg.close()
+	return counter.n, err
+}
+
+// SaveStream genera y devuelve los bytes del archivo ODP
+func (g *ODPGenerator) SaveStream() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := g.WriteTo(&buf); err != nil {
 		return nil, err
 	}
-
 	return buf.Bytes(), nil
 }
 
-// Modificar Save para usar SaveStream
+// validate comprueba antes de escribir nada que la presentación se puede guardar
+func (g *ODPGenerator) validate() error {
+	if err := g.validateMasterPages(); err != nil {
+		return err
+	}
+	if err := g.validateClickActions(); err != nil {
+		return err
+	}
+	return g.validateMetadata()
+}
+
+// Save guarda la presentación en un archivo, añadiendo la extensión .odp si falta.
+// La presentación se escribe en un archivo temporal de la misma carpeta que después
+// sustituye al archivo, de modo que si falla la escritura el archivo que ya existía
+// se conserva intacto.
 func (g *ODPGenerator) Save(filename string) error {
 	if !strings.HasSuffix(filename, ".odp") {
 		filename += ".odp"
 	}
+	if err := g.validate(); err != nil {
+		return err
+	}
 
-	// Obtener los bytes usando SaveStream
-	data, err := g.SaveStream()
+	// El archivo nuevo conserva los permisos del que sustituye
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+"-*.tmp")
 	if err != nil {
 		return err
 	}
-
-	// Escribir el archivo
-	return os.WriteFile(filename, data, 0644)
+	temp := file.Name()
+	_, err = g.WriteTo(file)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(temp, mode)
+	}
+	if err == nil {
+		err = os.Rename(temp, filename)
+	}
+	if err != nil {
+		os.Remove(temp)
+		return err
+	}
+	return nil
 }
 
 // generateStyleName genera un identificador único para un estilo de texto
@@ -592,6 +629,454 @@ func generateStyleName(style TextStyle) string {
 	return strings.Join(parts, "_")
 }
 
+// scopedElement asocia un elemento con el ámbito (diapositiva o página maestra) al que
+// pertenece, para que los nombres de sus estilos automáticos sean únicos
+type scopedElement struct {
+	Scope string
+	Data  interface{}
+}
+
+// elementTemplates contiene las plantillas de los elementos de dibujo, compartidas por
+// las diapositivas (content.xml) y las páginas maestras (styles.xml)
+const elementTemplates = `
+{{define "paragraphStyles"}}
+    {{range .Data}}
+        {{if .Props}}
+            {{template "paragraphStyle" (scoped (generateParaStyleID $.Scope .ZIndex .Props) .Props)}}
+        {{end}}
+        {{$box := .}}
+        {{range $index, $paragraph := .Paragraphs}}
+            {{if $paragraph.Props}}
+            {{template "paragraphStyle" (scoped (richParagraphStyleID $.Scope $box $index) $paragraph.Props)}}
+            {{end}}
+        {{end}}
+    {{end}}
+{{end}}
+
+{{define "paragraphStyle"}}
+    {{if ne .Scope "Pdefault"}}
+    <style:style style:name="{{.Scope}}" style:family="paragraph">
+        <style:paragraph-properties 
+            fo:margin-left="{{printf "%.2fcm" .Data.LeftIndent}}"
+            fo:margin-right="{{printf "%.2fcm" .Data.RightIndent}}"
+            fo:text-indent="{{printf "%.2fcm" .Data.FirstLineIndent}}"
+            {{if .Data.HorizontalAlign}}fo:text-align="{{.Data.HorizontalAlign}}"{{end}}
+        />
+    </style:style>
+    {{end}}
+{{end}}
+
+{{define "runs"}}{{range .}}{{if .Link}}<text:a xlink:type="simple" xlink:href="{{html .Link}}">{{end}}<text:span text:style-name="{{generateStyleName .Style}}">{{.Text}}</text:span>{{if .Link}}</text:a>{{end}}{{end}}{{end}}
+
+{{define "clickAction"}}
+    {{with clickAction .Scope .Data}}
+    <office:event-listeners>
+        <presentation:event-listener script:event-name="dom:click" {{actionAttributes .}}/>
+    </office:event-listeners>
+    {{end}}
+{{end}}
+
+{{define "element"}}
+    {{if eq .Data.Type "textbox"}}
+    {{template "textbox" (scoped .Scope .Data.Data)}}
+    {{else if eq .Data.Type "list"}}
+    {{template "list" (scoped .Scope .Data.Data)}}
+    {{else if eq .Data.Type "table"}}
+    {{template "table" (scoped .Scope .Data.Data)}}
+    {{else if eq .Data.Type "shape"}}
+    {{template "shape" (scoped .Scope .Data.Data)}}
+    {{else if eq .Data.Type "chart"}}
+    {{template "chart" (scoped .Scope .Data.Data)}}
+    {{else}}
+    {{template "image" (scoped .Scope .Data.Data)}}
+    {{end}}
+{{end}}
+
+{{define "textbox"}}
+    {{with .Data}}
+    <draw:frame draw:style-name="{{if and .Props .Props.VerticalAlign}}{{generateVerticalAlign .Props.VerticalAlign}}{{else}}gr2{{end}}" draw:layer="{{layer $.Scope}}"
+               svg:width="{{.Width}}" svg:height="{{.Height}}" 
+               svg:x="{{.X}}" svg:y="{{.Y}}"
+               draw:z-index="{{.ZIndex}}"{{elementID $.Scope .ZIndex}}
+               {{if not (isMaster $.Scope)}}presentation:class="outline"{{end}}>
+        <draw:text-box text:anchor-type="paragraph">
+            {{if .Paragraphs}}
+            {{$box := .}}
+            {{range $index, $paragraph := .Paragraphs}}
+            <text:p text:style-name="{{richParagraphStyleID $.Scope $box $index}}"{{paragraphID $.Scope $box.ZIndex (print $index)}}>{{template "runs" .Runs}}</text:p>
+            {{end}}
+            {{else}}
+            {{$box := .}}
+            {{range $index, $content := contentParagraphs $.Scope .}}
+            <text:p text:style-name="{{generateParaStyleID $.Scope $box.ZIndex $box.Props}}"{{paragraphID $.Scope $box.ZIndex (print $index)}}>
+                <text:span text:style-name="{{generateStyleName $box.Style}}">{{$content}}</text:span>
+            </text:p>
+            {{end}}
+            {{end}}
+        </draw:text-box>
+        {{template "clickAction" (scoped $.Scope .ZIndex)}}
+    </draw:frame>
+    {{end}}
+{{end}}
+
+{{define "listStyles"}}
+    {{range .Data}}
+    <style:style style:name="{{listParagraphStyleName $.Scope .ZIndex}}" style:family="paragraph">
+        <style:paragraph-properties {{if .Props.HorizontalAlign}}fo:text-align="{{.Props.HorizontalAlign}}"{{end}}/>
+    </style:style>
+    <text:list-style style:name="{{listStyleName $.Scope .ZIndex}}">
+        {{range $index, $level := .Levels}}
+        {{if $level.Numbering}}
+        {{$n := numbering $level.Numbering}}
+        <text:list-level-style-number text:level="{{inc $index}}" style:num-prefix="{{html $n.Prefix}}" style:num-format="{{$n.Format}}" style:num-suffix="{{html $n.Suffix}}">
+            <style:list-level-properties text:space-before="{{listIndent $index}}" text:min-label-width="0.80cm"/>
+        </text:list-level-style-number>
+        {{else if $level.BulletImageName}}
+        <text:list-level-style-image text:level="{{inc $index}}" xlink:href="{{$level.BulletImageName}}" xlink:type="simple" xlink:show="embed" xlink:actuate="onLoad">
+            <style:list-level-properties text:space-before="{{listIndent $index}}" text:min-label-width="0.80cm" fo:width="0.40cm" fo:height="0.40cm"/>
+        </text:list-level-style-image>
+        {{else}}
+        <text:list-level-style-bullet text:level="{{inc $index}}" text:bullet-char="{{html $level.BulletChar}}">
+            <style:list-level-properties text:space-before="{{listIndent $index}}" text:min-label-width="0.80cm"/>
+        </text:list-level-style-bullet>
+        {{end}}
+        {{end}}
+    </text:list-style>
+    {{end}}
+{{end}}
+
+{{define "list"}}
+    {{with .Data}}
+    <draw:frame draw:style-name="{{if and .Props .Props.VerticalAlign}}{{generateVerticalAlign .Props.VerticalAlign}}{{else}}gr2{{end}}" draw:layer="{{layer $.Scope}}"
+               svg:width="{{.Width}}" svg:height="{{.Height}}" 
+               svg:x="{{.X}}" svg:y="{{.Y}}"
+               draw:z-index="{{.ZIndex}}"{{elementID $.Scope .ZIndex}}
+               {{if not (isMaster $.Scope)}}presentation:class="outline"{{end}}>
+        <draw:text-box text:anchor-type="paragraph">
+            <text:list text:style-name="{{listStyleName $.Scope .ZIndex}}">
+                {{template "listItems" (listLevel $.Scope .ZIndex "" .Items)}}
+            </text:list>
+        </draw:text-box>
+        {{template "clickAction" (scoped $.Scope .ZIndex)}}
+    </draw:frame>
+    {{end}}
+{{end}}
+
+{{define "listItems"}}
+    {{$level := .}}
+    {{range $index, $item := .Items}}
+    <text:list-item>
+        <text:p text:style-name="{{$level.StyleName}}"{{paragraphID $level.Scope $level.ZIndex (print $level.Path $index)}}>{{template "runs" .Runs}}</text:p>
+        {{if .Items}}
+        <text:list>
+            {{template "listItems" (listLevel $level.Scope $level.ZIndex (print $level.Path $index "_") .Items)}}
+        </text:list>
+        {{end}}
+    </text:list-item>
+    {{end}}
+{{end}}
+
+{{define "tableStyles"}}
+    {{range .Data}}
+    {{$table := .}}
+    {{range $c, $width := .ColumnWidths}}
+    <style:style style:name="{{tableStyleName "co" $.Scope $table.ZIndex $c}}" style:family="table-column">
+        <style:table-column-properties style:column-width="{{$width}}"/>
+    </style:style>
+    {{end}}
+    <style:style style:name="{{tableStyleName "ro" $.Scope .ZIndex}}" style:family="table-row">
+        <style:table-row-properties style:row-height="{{.RowHeight}}"/>
+    </style:style>
+    {{range $r, $row := .Rows}}
+    {{range $c, $cell := $row}}
+    {{if and $cell.Style (not $cell.Covered)}}
+    <style:style style:name="{{tableStyleName "ce" $.Scope $table.ZIndex $r $c}}" style:family="table-cell">
+        <style:graphic-properties {{if $cell.Style.FillColor}}draw:fill="solid" draw:fill-color="{{html $cell.Style.FillColor}}"{{else}}draw:fill="none"{{end}}
+            {{if $cell.Style.VerticalAlign}}draw:textarea-vertical-align="{{html $cell.Style.VerticalAlign}}"{{end}}/>
+        <style:paragraph-properties {{if $cell.Style.BorderColor}}fo:border="{{printf "%.2fpt" $cell.Style.BorderWidth}} solid {{html $cell.Style.BorderColor}}"{{else}}fo:border="none"{{end}}
+            {{if $cell.Style.HorizontalAlign}}fo:text-align="{{html $cell.Style.HorizontalAlign}}"{{end}}/>
+    </style:style>
+    {{end}}
+    {{end}}
+    {{end}}
+    {{end}}
+{{end}}
+
+{{define "table"}}
+    {{with .Data}}
+    {{$table := .}}
+    <draw:frame draw:style-name="gr2" draw:layer="{{layer $.Scope}}"
+               svg:width="{{.Width}}" svg:height="{{.Height}}" 
+               svg:x="{{.X}}" svg:y="{{.Y}}"
+               draw:z-index="{{.ZIndex}}"{{elementID $.Scope .ZIndex}}>
+        <table:table{{if .HeaderRow}} table:use-first-row-styles="true"{{end}}>
+            {{range $c, $width := .ColumnWidths}}
+            <table:table-column table:style-name="{{tableStyleName "co" $.Scope $table.ZIndex $c}}"/>
+            {{end}}
+            {{range $r, $row := .Rows}}
+            <table:table-row table:style-name="{{tableStyleName "ro" $.Scope $table.ZIndex}}">
+                {{range $c, $cell := $row}}
+                {{if $cell.Covered}}
+                <table:covered-table-cell/>
+                {{else}}
+                <table:table-cell{{if $cell.Style}} table:style-name="{{tableStyleName "ce" $.Scope $table.ZIndex $r $c}}"{{end}}{{if gt $cell.ColSpan 1}} table:number-columns-spanned="{{$cell.ColSpan}}"{{end}}{{if gt $cell.RowSpan 1}} table:number-rows-spanned="{{$cell.RowSpan}}"{{end}}>
+                    <text:p><text:span text:style-name="{{generateStyleName (cellTextStyle $table $cell)}}">{{$cell.Text}}</text:span></text:p>
+                </table:table-cell>
+                {{end}}
+                {{end}}
+            </table:table-row>
+            {{end}}
+        </table:table>
+        {{template "clickAction" (scoped $.Scope .ZIndex)}}
+    </draw:frame>
+    {{end}}
+{{end}}
+
+{{define "shapeStyles"}}
+    {{range .Data}}
+    <style:style style:name="{{shapeStyleName $.Scope .ZIndex}}" style:family="graphic">
+        <style:graphic-properties
+            {{with .ShapeStyle}}
+            {{if .FillColor}}draw:fill="solid" draw:fill-color="{{html .FillColor}}"{{else}}draw:fill="none"{{end}}
+            {{if not .StrokeColor}}draw:stroke="none"{{else if strokeDashName .StrokeDash}}draw:stroke="dash" draw:stroke-dash="{{strokeDashName .StrokeDash}}"{{else}}draw:stroke="solid"{{end}}
+            {{if .StrokeColor}}svg:stroke-color="{{html .StrokeColor}}" svg:stroke-width="{{printf "%.2fpt" .StrokeWidth}}"{{end}}
+            {{if .StartArrow}}draw:marker-start="goodpArrow" draw:marker-start-width="{{arrowWidth .StrokeWidth}}"{{end}}
+            {{if .EndArrow}}draw:marker-end="goodpArrow" draw:marker-end-width="{{arrowWidth .StrokeWidth}}"{{end}}
+            {{end}}
+            {{if and .Props .Props.VerticalAlign}}draw:textarea-vertical-align="{{.Props.VerticalAlign}}"{{end}}/>
+    </style:style>
+    {{if and .Text .Props}}
+    {{template "paragraphStyle" (scoped (generateParaStyleID $.Scope .ZIndex .Props) .Props)}}
+    {{end}}
+    {{end}}
+{{end}}
+
+{{define "shape"}}
+    {{with .Data}}
+    {{if eq .Type "line"}}
+    <draw:line draw:style-name="{{shapeStyleName $.Scope .ZIndex}}" draw:layer="{{layer $.Scope}}"
+               svg:x1="{{.X1}}" svg:y1="{{.Y1}}" svg:x2="{{.X2}}" svg:y2="{{.Y2}}"
+               draw:z-index="{{.ZIndex}}"{{elementID $.Scope .ZIndex}}>
+        {{template "clickAction" (scoped $.Scope .ZIndex)}}
+        {{template "shapeText" (scoped $.Scope .)}}
+    </draw:line>
+    {{else if eq .Type "polygon"}}
+    <draw:polygon draw:style-name="{{shapeStyleName $.Scope .ZIndex}}" draw:layer="{{layer $.Scope}}"
+               svg:width="{{.Width}}" svg:height="{{.Height}}" 
+               svg:x="{{.X}}" svg:y="{{.Y}}"
+               svg:viewBox="{{.ViewBox}}" draw:points="{{.Points}}"
+               draw:z-index="{{.ZIndex}}"{{elementID $.Scope .ZIndex}}>
+        {{template "clickAction" (scoped $.Scope .ZIndex)}}
+        {{template "shapeText" (scoped $.Scope .)}}
+    </draw:polygon>
+    {{else}}
+    <draw:{{.Type}} draw:style-name="{{shapeStyleName $.Scope .ZIndex}}" draw:layer="{{layer $.Scope}}"
+               svg:width="{{.Width}}" svg:height="{{.Height}}" 
+               svg:x="{{.X}}" svg:y="{{.Y}}"
+               {{if and (eq .Type "rect") .ShapeStyle.CornerRadius}}draw:corner-radius="{{printf "%.2fcm" .ShapeStyle.CornerRadius}}"{{end}}
+               draw:z-index="{{.ZIndex}}"{{elementID $.Scope .ZIndex}}>
+        {{template "clickAction" (scoped $.Scope .ZIndex)}}
+        {{template "shapeText" (scoped $.Scope .)}}
+    </draw:{{.Type}}>
+    {{end}}
+    {{end}}
+{{end}}
+
+{{define "shapeText"}}
+    {{with .Data}}
+    {{if .Text}}
+    <text:p text:style-name="{{generateParaStyleID $.Scope .ZIndex .Props}}"><text:span text:style-name="{{generateStyleName .Style}}">{{.Text}}</text:span></text:p>
+    {{end}}
+    {{end}}
+{{end}}
+
+{{define "chart"}}
+    {{with .Data}}
+    <draw:frame draw:style-name="gr2" draw:layer="{{layer $.Scope}}"
+               svg:width="{{.Width}}" svg:height="{{.Height}}" 
+               svg:x="{{.X}}" svg:y="{{.Y}}"
+               draw:z-index="{{.ZIndex}}"{{elementID $.Scope .ZIndex}}
+               {{if not (isMaster $.Scope)}}presentation:class="chart"{{end}}>
+        <draw:object xlink:href="./{{.ObjectName}}" xlink:type="simple" xlink:show="embed" xlink:actuate="onLoad"/>
+        {{template "clickAction" (scoped $.Scope .ZIndex)}}
+    </draw:frame>
+    {{end}}
+{{end}}
+
+{{define "image"}}
+    {{with .Data}}
+    <draw:frame draw:style-name="gr2" draw:layer="{{layer $.Scope}}"
+               svg:width="{{.Width}}" svg:height="{{.Height}}" 
+               svg:x="{{.X}}" svg:y="{{.Y}}"
+               draw:z-index="{{.ZIndex}}"{{elementID $.Scope .ZIndex}}
+               {{if not (isMaster $.Scope)}}presentation:class="graphic"{{end}}>
+        <draw:image xlink:href="{{.Name}}" xlink:type="simple" xlink:show="embed" xlink:actuate="onLoad"/>
+        {{template "clickAction" (scoped $.Scope .ZIndex)}}
+    </draw:frame>
+    {{end}}
+{{end}}
+`
+
+// elementFuncs devuelve las funciones usadas por las plantillas de los elementos de dibujo
+func elementFuncs() template.FuncMap {
+	return template.FuncMap{
+		"scoped": func(scope string, data interface{}) scopedElement {
+			return scopedElement{Scope: scope, Data: data}
+		},
+		// Los ámbitos de las páginas maestras empiezan por "M"
+		"isMaster": func(scope string) bool {
+			return strings.HasPrefix(scope, "M")
+		},
+		"layer": func(scope string) string {
+			if strings.HasPrefix(scope, "M") {
+				return "backgroundobjects"
+			}
+			return "layout"
+		},
+		"generateVerticalAlign": func(align string) string {
+			switch align {
+			case "top":
+				return "V1"
+			case "middle":
+				return "V2"
+			case "bottom":
+				return "V3"
+			default:
+				return "gr2" // default style sin alineación vertical
+			}
+		},
+		"inc": func(i int) int {
+			return i + 1
+		},
+		"numbering": func(numbering string) map[string]string {
+			prefix, format, suffix, _ := parseNumbering(numbering)
+			return map[string]string{"Prefix": prefix, "Format": format, "Suffix": suffix}
+		},
+		"listIndent": func(level int) string {
+			return fmt.Sprintf("%.2fcm", float64(level)*0.8)
+		},
+		"listStyleName":          listStyleName,
+		"listParagraphStyleName": listParagraphStyleName,
+		"generateParaStyleID":    generateParaStyleID,
+		"richParagraphStyleID":   richParagraphStyleID,
+		"generateStyleName":      generateStyleName,
+		"tableStyleName":         tableStyleName,
+		"cellTextStyle":          cellTextStyle,
+		"shapeStyleName":         shapeStyleName,
+		"strokeDashName":         strokeDashName,
+		// Solo los elementos animados de las diapositivas tienen identificador
+		"elementID": func(scope string, zIndex int) string {
+			return ""
+		},
+		"paragraphID": func(scope string, zIndex int, path string) string {
+			return ""
+		},
+		"contentParagraphs": func(scope string, tb TextBox) []string {
+			return []string{tb.Content}
+		},
+		"listLevel": newListLevel,
+		"clickAction": func(scope string, zIndex int) *ClickAction {
+			return nil
+		},
+		"actionAttributes": func(action ClickAction) string {
+			return ""
+		},
+		"arrowWidth": func(strokeWidth float64) string {
+			// La punta de flecha crece con el grosor de la línea
+			return fmt.Sprintf("%.2fcm", 0.25+3*strokeWidth*2.54/72)
+		},
+	}
+}
+
+// generateParaStyleID genera un identificador único para un estilo de párrafo
+func generateParaStyleID(scope string, textboxZIndex int, props *TextProperties) string {
+	// Crear un identificador único basado en las propiedades
+	var parts []string
+
+	if props != nil {
+		if props.HorizontalAlign != "" {
+			parts = append(parts, fmt.Sprintf("h%s", props.HorizontalAlign))
+		}
+		parts = append(parts, fmt.Sprintf("l%.2f", props.LeftIndent))
+		parts = append(parts, fmt.Sprintf("r%.2f", props.RightIndent))
+		parts = append(parts, fmt.Sprintf("f%.2f", props.FirstLineIndent))
+	}
+
+	// Si no hay propiedades especiales, usar un identificador base
+	if len(parts) == 0 {
+		return "Pdefault"
+	}
+
+	// Crear un ID único combinando el ámbito, zindex y propiedades
+	return fmt.Sprintf("P%s_%d_%s", scope, textboxZIndex, strings.Join(parts, "_"))
+}
+
+// usedTextStyles devuelve los estilos de texto usados en las diapositivas y
+// en las páginas maestras, sin repetir
+func (g *ODPGenerator) usedTextStyles() []TextStyle {
+	styles := make([]TextStyle, 0)
+	seen := make(map[string]bool)
+
+	addStyle := func(style TextStyle) {
+		name := generateStyleName(style)
+		if !seen[name] {
+			seen[name] = true
+			styles = append(styles, style)
+		}
+	}
+	add := func(textBoxes []*TextBox) {
+		for _, tb := range textBoxes {
+			addStyle(tb.Style)
+			for _, p := range tb.Paragraphs {
+				for _, run := range p.Runs {
+					addStyle(run.Style)
+				}
+			}
+		}
+	}
+
+	var addItems func(items []ListItem)
+	addItems = func(items []ListItem) {
+		for _, item := range items {
+			for _, run := range item.Runs {
+				addStyle(run.Style)
+			}
+			addItems(item.Items)
+		}
+	}
+
+	for _, slide := range g.Slides {
+		add(slide.TextBoxes)
+		for _, list := range slide.Lists {
+			addItems(list.Items)
+		}
+		for _, p := range slide.Notes {
+			for _, run := range p.Runs {
+				addStyle(run.Style)
+			}
+		}
+		for _, shape := range slide.Shapes {
+			if shape.Text != "" {
+				addStyle(shape.Style)
+			}
+		}
+		for _, table := range slide.Tables {
+			for _, row := range table.Rows {
+				for _, cell := range row {
+					if !cell.Covered {
+						addStyle(cellTextStyle(*table, cell))
+					}
+				}
+			}
+		}
+	}
+	for _, master := range g.MasterPages {
+		add(master.TextBoxes)
+	}
+
+	return styles
+}
+
 // Modificar writeContent para usar la función extraída
 func (g *ODPGenerator) writeContent(writer io.Writer) error {
 	const contentTemplate = `<?xml version="1.0" encoding="UTF-8"?>
@@ -608,6 +1093,8 @@ func (g *ODPGenerator) writeContent(writer io.Writer) error {
     xmlns:number="urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0"
     xmlns:presentation="urn:oasis:names:tc:opendocument:xmlns:presentation:1.0"
     xmlns:svg="urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"
+    xmlns:smil="urn:oasis:names:tc:opendocument:xmlns:smil-compatible:1.0"
+    xmlns:anim="urn:oasis:names:tc:opendocument:xmlns:animation:1.0"
     xmlns:chart="urn:oasis:names:tc:opendocument:xmlns:chart:1.0"
     xmlns:dr3d="urn:oasis:names:tc:opendocument:xmlns:dr3d:1.0"
     xmlns:math="http://www.w3.org/1998/Math/MathML"
@@ -625,6 +1112,9 @@ func (g *ODPGenerator) writeContent(writer io.Writer) error {
     <office:scripts/>
     <office:font-face-decls/>
     <office:automatic-styles>
+        {{if usesHeaderFooter}}
+        {{template "dateTimeStyles"}}
+        {{end}}
         {{if .Background}}
         <style:style style:family="drawing-page" style:name="backgroundStyle">
             <style:drawing-page-properties 
@@ -639,10 +1129,7 @@ func (g *ODPGenerator) writeContent(writer io.Writer) error {
                 {{end}}
                 presentation:background-objects-visible="true" 
                 presentation:background-visible="false"
-                presentation:display-header="false" 
-                presentation:display-footer="false" 
-                presentation:display-page-number="false" 
-                presentation:display-date-time="false"/>
+                {{displayAttributes .HeaderFooter}}/>
         </style:style>
         {{end}}
         {{range $index, $slide := .Slides}}
@@ -660,19 +1147,41 @@ func (g *ODPGenerator) writeContent(writer io.Writer) error {
                     {{end}}
                     presentation:background-objects-visible="true" 
                     presentation:background-visible="false"
-                    presentation:display-header="false" 
-                    presentation:display-footer="false" 
-                    presentation:display-page-number="false" 
-                    presentation:display-date-time="false"/>
+                    {{displayAttributes (slideHeaderFooter $slide)}}/>
             </style:style>
             {{end}}
         {{end}}
+        {{range slidePageStyles}}
+        <style:style style:family="drawing-page" style:name="{{.Name}}">
+            <style:drawing-page-properties 
+                {{if .Background}}
+                {{if eq .Background.Type 0}}
+                draw:fill="bitmap" 
+                draw:fill-image-name="{{.FillImage}}" 
+                style:repeat="stretch"
+                draw:background-size="border" 
+                {{else}}
+                draw:fill="solid"
+                draw:fill-color="{{.Background.Color}}"
+                {{end}}
+                presentation:background-objects-visible="true" 
+                presentation:background-visible="false"
+                {{else}}
+                presentation:background-visible="true"
+                presentation:background-objects-visible="true"
+                {{end}}
+                {{displayAttributes .HeaderFooter}}
+                {{with .Transition}}{{transitionAttributes .}}{{end}}>
+                {{if and .Transition .Transition.SoundName}}
+                <presentation:sound xlink:href="{{.Transition.SoundName}}" xlink:type="simple" xlink:show="new" xlink:actuate="onRequest"/>
+                {{end}}
+            </style:drawing-page-properties>
+        </style:style>
+        {{end}}
         <style:style style:name="dp1" style:family="drawing-page">
             <style:drawing-page-properties presentation:background-visible="true"
                                          presentation:background-objects-visible="true"
-                                         presentation:display-footer="true"
-                                         presentation:display-page-number="false"
-                                         presentation:display-date-time="true"/>
+                                         {{displayAttributes .HeaderFooter}}/>
         </style:style>
 
         <style:style style:name="gr2" style:family="graphic">
@@ -692,110 +1201,101 @@ func (g *ODPGenerator) writeContent(writer io.Writer) error {
         <style:style style:name="V3" style:family="graphic">
             <style:graphic-properties draw:textarea-vertical-align="bottom"/>
         </style:style>
-		{{range $slideIndex, $slide := .Slides}}
-            {{range $textboxIndex, $textbox := .TextBoxes}}
-                {{if .Props}}
-                    {{$styleID := generateParaStyleID $slideIndex .ZIndex .Props}}
-                    {{if ne $styleID "Pdefault"}}
-                    <style:style style:name="{{$styleID}}" style:family="paragraph">
-                        <style:paragraph-properties 
-                            fo:margin-left="{{printf "%.2fcm" .Props.LeftIndent}}"
-                            fo:margin-right="{{printf "%.2fcm" .Props.RightIndent}}"
-                            fo:text-indent="{{printf "%.2fcm" .Props.FirstLineIndent}}"
-                            {{if .Props.HorizontalAlign}}fo:text-align="{{.Props.HorizontalAlign}}"{{end}}
-                        />
-                    </style:style>
-                    {{end}}
-                {{end}}
+        {{range $slideIndex, $slide := .Slides}}
+            {{template "paragraphStyles" (scoped (print $slideIndex) .TextBoxes)}}
+            {{template "listStyles" (scoped (print $slideIndex) .Lists)}}
+            {{template "tableStyles" (scoped (print $slideIndex) .Tables)}}
+            {{template "shapeStyles" (scoped (print $slideIndex) .Shapes)}}
+            {{range $index, $paragraph := .Notes}}
+            {{if $paragraph.Props}}
+            {{template "paragraphStyle" (scoped (notesParagraphStyleID (print $slideIndex) $slide.Notes $index) $paragraph.Props)}}
+            {{end}}
             {{end}}
         {{end}}
     </office:automatic-styles>
     <office:body>
         <office:presentation>
+            {{$decls := headerFooterDecls}}
+            {{range $decls.Footers}}
+            <presentation:footer-decl presentation:name="{{.Name}}">{{.Text}}</presentation:footer-decl>
+            {{end}}
+            {{range $decls.DateTimes}}
+            <presentation:date-time-decl presentation:name="{{.Name}}" presentation:source="{{.Source}}"{{if .DataStyle}} style:data-style-name="{{.DataStyle}}"{{end}}>{{.Text}}</presentation:date-time-decl>
+            {{end}}
             {{range $slideIndex, $slide := .Slides}}
-            <draw:page draw:name="page{{$slideIndex}}" 
-                      {{if $slide.Background}}
-                      draw:style-name="slideBackground{{$slideIndex}}"
-                      {{else if $.Background}}
-                      draw:style-name="backgroundStyle"
-                      {{else}}
-                      draw:style-name="dp1"
-                      {{end}}
-                      draw:master-page-name="Default">
+            {{$slideDecls := index $decls.Slides $slideIndex}}
+            <draw:page draw:name="{{html (pageName $slideIndex)}}" 
+                      draw:style-name="{{pageStyleName $slideIndex $slide}}"
+                      draw:master-page-name="{{html (masterPageName $slide)}}"{{if $slideDecls.Footer}}
+                      presentation:use-footer-name="{{$slideDecls.Footer}}"{{end}}{{if $slideDecls.DateTime}}
+                      presentation:use-date-time-name="{{$slideDecls.DateTime}}"{{end}}>
                 {{range .SortedElements}}
-                    {{if eq .Type "textbox"}}
-                    {{with .Data}}
-                    <draw:frame draw:style-name="{{if and .Props .Props.VerticalAlign}}{{generateVerticalAlign .Props.VerticalAlign}}{{else}}gr2{{end}}" draw:layer="layout"
-                               svg:width="{{.Width}}" svg:height="{{.Height}}" 
-                               svg:x="{{.X}}" svg:y="{{.Y}}"
-                               draw:z-index="{{.ZIndex}}"
-                               presentation:class="outline">
-                        <draw:text-box text:anchor-type="paragraph">
-                            <text:p text:style-name="{{generateParaStyleID $slideIndex .ZIndex .Props}}">
-                                <text:span text:style-name="{{generateStyleName .Style}}">{{.Content}}</text:span>
-                            </text:p>
+                    {{template "element" (scoped (print $slideIndex) .)}}
+                {{end}}
+                {{if .Animations}}
+                {{template "animations" (scoped (print $slideIndex) $slide)}}
+                {{end}}
+                {{if .Notes}}
+                {{$notes := notesGeometry}}
+                <presentation:notes>
+                    <draw:page-thumbnail draw:layer="layout" svg:width="{{$notes.ThumbnailWidth}}" svg:height="{{$notes.ThumbnailHeight}}"
+                                         svg:x="{{$notes.ThumbnailX}}" svg:y="{{$notes.ThumbnailY}}"
+                                         draw:page-number="{{inc $slideIndex}}" presentation:class="page"/>
+                    <draw:frame draw:layer="layout" svg:width="{{$notes.TextWidth}}" svg:height="{{$notes.TextHeight}}"
+                                svg:x="{{$notes.TextX}}" svg:y="{{$notes.TextY}}"
+                                presentation:class="notes" presentation:placeholder="false">
+                        <draw:text-box>
+                            {{range $index, $paragraph := .Notes}}
+                            <text:p text:style-name="{{notesParagraphStyleID (print $slideIndex) $slide.Notes $index}}">{{template "runs" .Runs}}</text:p>
+                            {{end}}
                         </draw:text-box>
                     </draw:frame>
-                    {{end}}
-                    {{else}}
-                    {{with .Data}}
-                    <draw:frame draw:style-name="gr2" draw:layer="layout"
-                               svg:width="{{.Width}}" svg:height="{{.Height}}" 
-                               svg:x="{{.X}}" svg:y="{{.Y}}"
-                               draw:z-index="{{.ZIndex}}"
-                               presentation:class="graphic">
-                        <draw:image xlink:href="{{.Name}}" xlink:type="simple" xlink:show="embed" xlink:actuate="onLoad"/>
-                    </draw:frame>
-                    {{end}}
-                    {{end}}
+                </presentation:notes>
                 {{end}}
             </draw:page>
             {{end}}
         </office:presentation>
     </office:body>
 </office:document-content>`
-	tmpl, err := template.New("content").Funcs(template.FuncMap{
+	tmpl, err := template.New("content").Funcs(elementFuncs()).Funcs(template.FuncMap{
 		"sub": func(a, b float64) float64 {
 			return a - b
 		},
-		"generateVerticalAlign": func(align string) string {
-			switch align {
-			case "top":
-				return "V1"
-			case "middle":
-				return "V2"
-			case "bottom":
-				return "V3"
-			default:
-				return "gr2" // default style sin alineación vertical
-			}
-		},
-		"generateParaStyleID": func(slideIndex int, textboxZIndex int, props *TextProperties) string {
-			// Crear un identificador único basado en las propiedades
-			var parts []string
-
-			if props != nil {
-				if props.HorizontalAlign != "" {
-					parts = append(parts, fmt.Sprintf("h%s", props.HorizontalAlign))
-				}
-				parts = append(parts, fmt.Sprintf("l%.2f", props.LeftIndent))
-				parts = append(parts, fmt.Sprintf("r%.2f", props.RightIndent))
-				parts = append(parts, fmt.Sprintf("f%.2f", props.FirstLineIndent))
-			}
-
-			// Si no hay propiedades especiales, usar un identificador base
-			if len(parts) == 0 {
-				return "Pdefault"
-			}
-
-			// Crear un ID único combinando slide, zindex y propiedades
-			return fmt.Sprintf("P%d_%d_%s", slideIndex, textboxZIndex, strings.Join(parts, "_"))
-		},
-		"generateStyleName": generateStyleName,
+		"masterPageName":        g.masterPageName,
+		"pageStyleName":         g.pageStyleName,
+		"notesGeometry":         g.notesGeometry,
+		"notesParagraphStyleID": notesParagraphStyleID,
+		"slidePageStyles":       g.slidePageStyles,
+		"displayAttributes":     displayAttributes,
+		"slideHeaderFooter":     g.slideHeaderFooter,
+		"usesHeaderFooter":      g.usesHeaderFooter,
+		"headerFooterDecls":     g.headerFooterDecls,
+		"transitionAttributes":  transitionAttributes,
+		"elementID":             g.elementID,
+		"paragraphID":           g.paragraphID,
+		"clickAction":           g.clickAction,
+		"actionAttributes":      g.actionAttributes,
+		"pageName":              g.pageName,
+		"contentParagraphs":     g.contentParagraphs,
+		"animationSteps":        animationSteps,
+		"animationTargetID":     animationTargetID,
+		"animationPreset":       animationPreset,
+		"flyValues":             flyValues,
+		"seconds":               formatSeconds,
+		"targetElement":         targetElement,
 	}).Parse(contentTemplate)
 	if err != nil {
 		return err
 	}
+	if _, err = tmpl.Parse(elementTemplates); err != nil {
+		return err
+	}
+	if _, err = tmpl.Parse(animationTemplates); err != nil {
+		return err
+	}
+	if _, err = tmpl.Parse(dateTimeStyles); err != nil {
+		return err
+	}
 	return tmpl.Execute(writer, g)
 }
 
@@ -808,8 +1308,12 @@ func (g *ODPGenerator) writeStyles(writer io.Writer) error {
                        xmlns:presentation="urn:oasis:names:tc:opendocument:xmlns:presentation:1.0"
                        xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"
                        xmlns:svg="urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"
-                       xmlns:xlink="http://www.w3.org/1999/xlink">
+                       xmlns:xlink="http://www.w3.org/1999/xlink"{{templateNamespaces}}>
+    {{if hasTemplate}}
+    <office:font-face-decls>{{templatePart "font-face-decls"}}</office:font-face-decls>
+    {{end}}
     <office:styles>
+        {{templatePart "styles"}}
         {{if and .Background (eq .Background.Type 0)}}
         <draw:fill-image draw:name="backgroundImage" xlink:href="{{.Background.Name}}" xlink:show="embed" xlink:actuate="onLoad"/>
         {{end}}
@@ -818,23 +1322,33 @@ func (g *ODPGenerator) writeStyles(writer io.Writer) error {
             <draw:fill-image draw:name="slideBackground{{$index}}" xlink:href="{{$slide.Background.Name}}" xlink:show="embed" xlink:actuate="onLoad"/>
             {{end}}
         {{end}}
-        {{$counter := 0}}
-        {{range .Slides}}
-            {{range .TextBoxes}}
-                {{$counter = inc $counter}}
-                <style:style style:name="{{generateStyleName .Style}}" style:family="text">
+        {{range $index, $master := .MasterPages}}
+            {{if and $master.Background (eq $master.Background.Type 0)}}
+            <draw:fill-image draw:name="masterBackground{{$index}}" xlink:href="{{$master.Background.Name}}" xlink:show="embed" xlink:actuate="onLoad"/>
+            {{end}}
+        {{end}}
+        {{if hasShapes}}
+        <draw:marker draw:name="goodpArrow" svg:viewBox="0 0 20 30" svg:d="M10 0l-10 30h20z"/>
+        <draw:stroke-dash draw:name="goodpDash" draw:style="rect" draw:dots1="1" draw:dots1-length="0.20cm" draw:distance="0.10cm"/>
+        <draw:stroke-dash draw:name="goodpDot" draw:style="round" draw:dots1="1" draw:dots1-length="0.02cm" draw:distance="0.08cm"/>
+        <draw:stroke-dash draw:name="goodpDashDot" draw:style="rect" draw:dots1="1" draw:dots1-length="0.20cm" draw:dots2="1" draw:dots2-length="0.02cm" draw:distance="0.10cm"/>
+        {{end}}
+        {{range textStyles}}
+                <style:style style:name="{{generateStyleName .}}" style:family="text">
                     <style:text-properties
-                        {{if .Style.FontFamily}}fo:font-family="{{.Style.FontFamily}}"{{end}}
-                        {{if .Style.FontSize}}fo:font-size="{{.Style.FontSize}}"{{end}}
-                        {{if .Style.Color}}fo:color="{{.Style.Color}}"{{end}}
-                        {{if .Style.Bold}}fo:font-weight="bold"{{end}}
-                        {{if .Style.Italic}}fo:font-style="italic"{{end}}
+                        {{if .FontFamily}}fo:font-family="{{.FontFamily}}"{{end}}
+                        {{if .FontSize}}fo:font-size="{{.FontSize}}"{{end}}
+                        {{if .Color}}fo:color="{{.Color}}"{{end}}
+                        {{if .Bold}}fo:font-weight="bold"{{end}}
+                        {{if .Italic}}fo:font-style="italic"{{end}}
                     />
                 </style:style>
-            {{end}}
         {{end}}
     </office:styles>
     <office:master-styles>
+        {{if hasTemplate}}
+        {{templatePart "master-styles"}}
+        {{else if not (registeredMaster "Default")}}
         <style:master-page style:name="Default" style:page-layout-name="PM1">
             <style:drawing-page-properties 
                 presentation:background-visible="true"
@@ -843,10 +1357,25 @@ func (g *ODPGenerator) writeStyles(writer io.Writer) error {
                 draw:fill-image-name="backgroundImage"
                 style:repeat="stretch"
                 draw:background-size="border"/>
+            {{template "placeholders" (defaultFooterPlaceholders nil)}}
+            {{template "masterNotes"}}
         </style:master-page>
+        {{end}}
+        {{range $index, $master := .MasterPages}}
+        <style:master-page style:name="{{html $master.Name}}" style:page-layout-name="{{pageLayoutName}}"{{if $master.Background}} draw:style-name="Mdp{{$index}}"{{end}}>
+            {{range $master.SortedElements}}
+                {{template "element" (scoped (printf "M%d" $index) .)}}
+            {{end}}
+            {{template "placeholders" $master.Placeholders}}
+            {{template "placeholders" (defaultFooterPlaceholders $master.Placeholders)}}
+            {{template "masterNotes"}}
+        </style:master-page>
+        {{end}}
     </office:master-styles>
     <office:automatic-styles>
-        <style:page-layout style:name="PM1">
+        {{templatePart "automatic-styles"}}
+        {{if or (not hasTemplate) .MasterPages}}
+        <style:page-layout style:name="{{pageLayoutName}}">
             <style:page-layout-properties fo:margin-top="0cm"
                                         fo:margin-bottom="0cm"
                                         fo:margin-left="0cm"
@@ -855,18 +1384,131 @@ func (g *ODPGenerator) writeStyles(writer io.Writer) error {
                                         fo:page-width="{{.SlideSize.Width}}cm"
                                         fo:page-height="{{.SlideSize.Height}}cm"/>
         </style:page-layout>
+        <style:page-layout style:name="{{notesLayoutName}}">
+            <style:page-layout-properties fo:margin-top="0cm"
+                                        fo:margin-bottom="0cm"
+                                        fo:margin-left="0cm"
+                                        fo:margin-right="0cm"
+                                        style:print-orientation="portrait"
+                                        fo:page-width="{{notesPageWidth}}"
+                                        fo:page-height="{{notesPageHeight}}"/>
+        </style:page-layout>
+        {{end}}
+        {{if .MasterPages}}
+        <style:style style:name="gr2" style:family="graphic">
+            <style:graphic-properties draw:stroke="none" draw:fill="none"/>
+        </style:style>
+        <style:style style:name="Pdefault" style:family="paragraph">
+            <style:paragraph-properties fo:text-align="left"/>
+        </style:style>
+        <style:style style:name="V1" style:family="graphic">
+            <style:graphic-properties draw:textarea-vertical-align="top"/>
+        </style:style>
+        <style:style style:name="V2" style:family="graphic">
+            <style:graphic-properties draw:textarea-vertical-align="middle"/>
+        </style:style>
+        <style:style style:name="V3" style:family="graphic">
+            <style:graphic-properties draw:textarea-vertical-align="bottom"/>
+        </style:style>
+        {{end}}
+        {{range $index, $master := .MasterPages}}
+            {{if $master.Background}}
+            <style:style style:family="drawing-page" style:name="Mdp{{$index}}">
+                <style:drawing-page-properties 
+                    {{if eq $master.Background.Type 0}}
+                    draw:fill="bitmap" 
+                    draw:fill-image-name="masterBackground{{$index}}" 
+                    style:repeat="stretch"
+                    draw:background-size="border" 
+                    {{else}}
+                    draw:fill="solid"
+                    draw:fill-color="{{$master.Background.Color}}"
+                    {{end}}
+                    presentation:background-visible="true"
+                    presentation:background-objects-visible="true"/>
+            </style:style>
+            {{end}}
+            {{template "paragraphStyles" (scoped (printf "M%d" $index) $master.TextBoxes)}}
+        {{end}}
     </office:automatic-styles>
 </office:document-styles>`
 
-	tmpl, err := template.New("styles").Funcs(template.FuncMap{
-		"inc": func(i int) int {
-			return i + 1
+	// Página de notas de las páginas maestras, con la miniatura de la diapositiva
+	// y el marcador de posición del texto de las notas
+	placeholdersTemplate := `{{define "placeholders"}}
+            {{range .}}
+            <draw:frame presentation:class="{{.Class}}" presentation:placeholder="true" draw:layer="backgroundobjects"
+                       svg:width="{{.Width}}" svg:height="{{.Height}}" 
+                       svg:x="{{.X}}" svg:y="{{.Y}}">
+                <draw:text-box>
+                    {{if eq .Class "footer"}}<text:p><presentation:footer/></text:p>
+                    {{else if eq .Class "header"}}<text:p><presentation:header/></text:p>
+                    {{else if eq .Class "date-time"}}<text:p><presentation:date-time/></text:p>
+                    {{else if eq .Class "page-number"}}<text:p>{{slideNumberField}}</text:p>
+                    {{end}}
+                </draw:text-box>
+            </draw:frame>
+            {{end}}
+{{end}}`
+
+	masterNotesTemplate := `{{define "masterNotes"}}
+            {{$notes := notesGeometry}}
+            <presentation:notes style:page-layout-name="{{notesLayoutName}}">
+                <draw:page-thumbnail presentation:class="page" draw:layer="backgroundobjects"
+                                     svg:width="{{$notes.ThumbnailWidth}}" svg:height="{{$notes.ThumbnailHeight}}"
+                                     svg:x="{{$notes.ThumbnailX}}" svg:y="{{$notes.ThumbnailY}}"/>
+                <draw:frame presentation:class="notes" presentation:placeholder="true" draw:layer="backgroundobjects"
+                            svg:width="{{$notes.TextWidth}}" svg:height="{{$notes.TextHeight}}"
+                            svg:x="{{$notes.TextX}}" svg:y="{{$notes.TextY}}">
+                    <draw:text-box/>
+                </draw:frame>
+            </presentation:notes>
+{{end}}`
+
+	tmpl, err := template.New("styles").Funcs(elementFuncs()).Funcs(template.FuncMap{
+		"textStyles":       g.usedTextStyles,
+		"registeredMaster": g.registeredMaster,
+		"hasShapes":        g.hasShapes,
+		"slideNumberField": func() string {
+			return slideNumberField
+		},
+		"pageLayoutName": func() string {
+			// Evitar conflictos con las disposiciones de página de la plantilla
+			if g.template != nil {
+				return "PMgoodp"
+			}
+			return "PM1"
+		},
+		"notesLayoutName": func() string {
+			if g.template != nil {
+				return "PMgoodpNotes"
+			}
+			return "PMnotes"
+		},
+		"notesPageWidth": func() string {
+			return fmt.Sprintf("%.2fcm", notesPageWidth)
+		},
+		"notesPageHeight": func() string {
+			return fmt.Sprintf("%.2fcm", notesPageHeight)
+		},
+		"notesGeometry":             g.notesGeometry,
+		"defaultFooterPlaceholders": g.defaultFooterPlaceholders,
+		"hasTemplate": func() bool {
+			return g.template != nil
+		},
+		"templatePart": g.templatePart,
+		"templateNamespaces": func() string {
+			return g.templateNamespaces("office", "style", "text", "draw", "presentation", "fo", "svg", "xlink")
 		},
-		"generateStyleName": generateStyleName,
 	}).Parse(stylesTemplate)
 	if err != nil {
 		return err
 	}
+	for _, text := range []string{elementTemplates, placeholdersTemplate, masterNotesTemplate} {
+		if _, err = tmpl.Parse(text); err != nil {
+			return err
+		}
+	}
 	return tmpl.Execute(writer, g)
 }
 
@@ -947,25 +1589,16 @@ func (g *ODPGenerator) writeManifest(writer io.Writer) error {
     <manifest:file-entry manifest:media-type="text/xml" manifest:full-path="content.xml"/>
     <manifest:file-entry manifest:media-type="text/xml" manifest:full-path="styles.xml"/>
     <manifest:file-entry manifest:media-type="text/xml" manifest:full-path="settings.xml"/>
+    <manifest:file-entry manifest:media-type="text/xml" manifest:full-path="meta.xml"/>
     <manifest:file-entry manifest:media-type="text/xml" manifest:full-path="configurations2/accelerator/current.xml"/>
-    {{if .Background}}
-    <manifest:file-entry manifest:media-type="image/{{extension .Background.Name}}" manifest:full-path="{{.Background.Name}}"/>
-    {{end}}
-    {{range .Slides}}
-        {{if .Background}}
-        <manifest:file-entry manifest:media-type="image/{{extension .Background.Name}}" manifest:full-path="{{.Background.Name}}"/>
-        {{end}}
-        {{range .Images}}
-    <manifest:file-entry manifest:media-type="image/{{extension .Name}}" manifest:full-path="{{.Name}}"/>
-        {{end}}
+    {{if .Slides}}<manifest:file-entry manifest:media-type="image/png" manifest:full-path="Thumbnails/thumbnail.png"/>{{end}}
+    {{range packageFiles}}
+    <manifest:file-entry manifest:media-type="{{.MediaType}}" manifest:full-path="{{.Name}}"/>
     {{end}}
 </manifest:manifest>`
 
 	tmpl, err := template.New("manifest").Funcs(template.FuncMap{
-		"extension": func(name string) string {
-			ext := filepath.Ext(name)
-			return strings.TrimPrefix(ext, ".")
-		},
+		"packageFiles": g.packageFiles,
 	}).Parse(manifestTemplate)
 	if err != nil {
 		return err
@@ -973,16 +1606,119 @@ func (g *ODPGenerator) writeManifest(writer io.Writer) error {
 	return tmpl.Execute(writer, g)
 }
 
+// packageFile representa un archivo binario (imagen, fondo...) que se guarda dentro del paquete ODP
+type packageFile struct {
+	Name      string
+	MediaType string
+	Data      []byte
+}
+
+// packageFiles devuelve los archivos binarios de la presentación sin duplicados.
+// Varias diapositivas pueden compartir la misma imagen (por ejemplo al leer un
+// archivo existente), por lo que cada ruta solo se incluye una vez.
+func (g *ODPGenerator) packageFiles() []packageFile {
+	files := make([]packageFile, 0)
+	seen := make(map[string]bool)
+
+	addFile := func(name, mediaType string, data []byte) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		files = append(files, packageFile{
+			Name:      name,
+			MediaType: mediaType,
+			Data:      data,
+		})
+	}
+	add := func(name string, data []byte) {
+		addFile(name, "image/"+strings.TrimPrefix(filepath.Ext(name), "."), data)
+	}
+
+	// Imagen de fondo global
+	if g.Background != nil && g.Background.Type == BackgroundImage {
+		add(g.Background.Name, g.Background.Data)
+	}
+
+	// Imágenes de fondo por diapositiva
+	for _, slide := range g.Slides {
+		if slide.Background != nil && slide.Background.Type == BackgroundImage {
+			add(slide.Background.Name, slide.Background.Data)
+		}
+	}
+
+	// Imágenes de las diapositivas
+	for _, slide := range g.Slides {
+		for _, img := range slide.Images {
+			add(img.Name, img.Data)
+		}
+	}
+
+	// Imágenes usadas como viñetas en las listas
+	for _, slide := range g.Slides {
+		for _, list := range slide.Lists {
+			for _, level := range list.Levels {
+				add(level.BulletImageName, level.BulletImage)
+			}
+		}
+	}
+
+	// Sonidos de las transiciones
+	transitions := []*Transition{g.Transition}
+	for _, slide := range g.Slides {
+		transitions = append(transitions, slide.Transition)
+	}
+	for _, transition := range transitions {
+		if transition != nil && transition.Type != TransitionNone {
+			addFile(transition.SoundName, soundMediaTypes[transition.SoundExtension], transition.Sound)
+		}
+	}
+
+	// Subdocumentos de los gráficos
+	for _, slide := range g.Slides {
+		for _, chart := range slide.Charts {
+			for _, file := range chart.files {
+				if !seen[file.Name] {
+					seen[file.Name] = true
+					files = append(files, file)
+				}
+			}
+		}
+	}
+
+	// Fondos e imágenes de las páginas maestras
+	for _, master := range g.MasterPages {
+		if master.Background != nil && master.Background.Type == BackgroundImage {
+			add(master.Background.Name, master.Background.Data)
+		}
+		for _, img := range master.Images {
+			add(img.Name, img.Data)
+		}
+	}
+
+	// Imágenes de la plantilla (logotipos, fondos de las páginas maestras...)
+	if g.template != nil {
+		for _, file := range g.template.files {
+			if !seen[file.Name] {
+				seen[file.Name] = true
+				files = append(files, file)
+			}
+		}
+	}
+
+	return files
+}
+
 // Añadir este método a la estructura Slide
 func (s *Slide) SortedElements() []DrawableElement {
-	elements := make([]DrawableElement, 0, len(s.TextBoxes)+len(s.Images))
+	elements := make([]DrawableElement, 0, len(s.TextBoxes)+len(s.Images)+len(s.Lists)+len(s.Tables)+len(s.Shapes)+len(s.Charts))
 
 	// Añadir TextBoxes
 	for _, tb := range s.TextBoxes {
 		elements = append(elements, DrawableElement{
 			Type:   "textbox",
 			ZIndex: tb.ZIndex,
-			Data:   tb,
+			Data:   *tb,
 		})
 	}
 
@@ -991,7 +1727,43 @@ func (s *Slide) SortedElements() []DrawableElement {
 		elements = append(elements, DrawableElement{
 			Type:   "image",
 			ZIndex: img.ZIndex,
-			Data:   img,
+			Data:   *img,
+		})
+	}
+
+	// Añadir Lists
+	for _, list := range s.Lists {
+		elements = append(elements, DrawableElement{
+			Type:   "list",
+			ZIndex: list.ZIndex,
+			Data:   *list,
+		})
+	}
+
+	// Añadir Tables
+	for _, table := range s.Tables {
+		elements = append(elements, DrawableElement{
+			Type:   "table",
+			ZIndex: table.ZIndex,
+			Data:   *table,
+		})
+	}
+
+	// Añadir Shapes
+	for _, shape := range s.Shapes {
+		elements = append(elements, DrawableElement{
+			Type:   "shape",
+			ZIndex: shape.ZIndex,
+			Data:   *shape,
+		})
+	}
+
+	// Añadir Charts
+	for _, chart := range s.Charts {
+		elements = append(elements, DrawableElement{
+			Type:   "chart",
+			ZIndex: chart.ZIndex,
+			Data:   *chart,
 		})
 	}
 
@@ -0,0 +1,122 @@
+package goodp
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"path"
+	"strings"
+)
+
+// zipVersion20 es la versión de ZIP de las cabeceras, la misma que usa archive/zip
+const zipVersion20 = 20
+
+// storedMediaTypes son los formatos que ya están comprimidos; volver a comprimirlos
+// solo gasta tiempo, así que se guardan tal cual
+var storedMediaTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/jpg":  true,
+	"image/gif":  true,
+	"audio/mpeg": true,
+	"audio/ogg":  true,
+}
+
+// storedExtensions son las extensiones de los formatos comprimidos, para los
+// archivos de las plantillas cuyo manifiesto no indica el tipo
+var storedExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+	".mp3": true, ".ogg": true,
+}
+
+// SetCompressionLevel establece el nivel de compresión de los archivos XML y del
+// resto de archivos sin comprimir del paquete, de flate.NoCompression (0) a
+// flate.BestCompression (9); flate.DefaultCompression (-1) es el nivel por defecto,
+// flate.BestSpeed (1) el más rápido y flate.HuffmanOnly (-2) solo aplica la
+// codificación de Huffman, sin buscar repeticiones.
+func (g *ODPGenerator) SetCompressionLevel(level int) error {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return fmt.Errorf("nivel de compresión inválido: %d (debe estar entre %d y %d)",
+			level, flate.HuffmanOnly, flate.BestCompression)
+	}
+	g.compressionLevel = level
+	g.compressionSet = true
+	return nil
+}
+
+// packageCompression devuelve el nivel de compresión del paquete: el indicado con
+// SetCompressionLevel o, si no se indicó (también en un ODPGenerator sin New),
+// flate.DefaultCompression
+func (g *ODPGenerator) packageCompression() int {
+	if !g.compressionSet {
+		return flate.DefaultCompression
+	}
+	return g.compressionLevel
+}
+
+// packageWriter escribe un paquete ODF: el archivo "mimetype" el primero y sin
+// comprimir, y el resto de archivos comprimidos o no según su formato
+type packageWriter struct {
+	zip *zip.Writer
+}
+
+// newPackageWriter crea un paquete que comprime con el nivel indicado
+func newPackageWriter(w io.Writer, level int) *packageWriter {
+	zipWriter := zip.NewWriter(w)
+	zipWriter.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(out, level)
+	})
+	return &packageWriter{zip: zipWriter}
+}
+
+// writeMimetype escribe el archivo "mimetype", que el estándar exige que sea el
+// primero del paquete, sin comprimir y sin campo extra, para que se pueda reconocer
+// el tipo del documento leyendo los primeros bytes
+func (p *packageWriter) writeMimetype() error {
+	return p.writeStored("mimetype", []byte(mimetypePresentation))
+}
+
+// create añade un archivo comprimido cuyo contenido se escribe después en el
+// io.Writer devuelto, sin conocer antes su tamaño
+func (p *packageWriter) create(name string) (io.Writer, error) {
+	return p.zip.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+}
+
+// writeFile añade un archivo; los formatos ya comprimidos se guardan tal cual
+func (p *packageWriter) writeFile(name, mediaType string, data []byte) error {
+	if storedMediaTypes[mediaType] || storedExtensions[strings.ToLower(path.Ext(name))] {
+		return p.writeStored(name, data)
+	}
+	w, err := p.create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writeStored añade un archivo sin comprimir, con el tamaño y el CRC en la cabecera
+// local en lugar de en un descriptor de datos posterior
+func (p *packageWriter) writeStored(name string, data []byte) error {
+	w, err := p.zip.CreateRaw(&zip.FileHeader{
+		Name:               name,
+		Method:             zip.Store,
+		CreatorVersion:     zipVersion20,
+		ReaderVersion:      zipVersion20,
+		CRC32:              crc32.ChecksumIEEE(data),
+		CompressedSize64:   uint64(len(data)),
+		UncompressedSize64: uint64(len(data)),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// close escribe el directorio central del paquete
+func (p *packageWriter) close() error {
+	return p.zip.Close()
+}
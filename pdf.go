@@ -0,0 +1,464 @@
+package goodp
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // Decodificador GIF para las imágenes
+	_ "image/jpeg" // Decodificador JPEG para las imágenes
+	_ "image/png"  // Decodificador PNG para las imágenes
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Caracteres de WinAnsiEncoding fuera de Latin-1
+var winAnsiSpecial = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‰': 0x89, '‹': 0x8B, '›': 0x9B,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+	'Œ': 0x8C, 'œ': 0x9C, 'Š': 0x8A, 'š': 0x9A, 'Ž': 0x8E, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// pdfDocument construye los objetos de un archivo PDF
+type pdfDocument struct {
+	objects [][]byte
+	fonts   map[string]string // Fuente estándar -> nombre del recurso (/F1...)
+	images  map[string]string // Imagen -> nombre del recurso (/Im1...)
+	// Recursos en el orden en que se crearon, con su número de objeto
+	resources []pdfResource
+}
+
+// pdfResource es una fuente o una imagen usada en las páginas
+type pdfResource struct {
+	Kind   string // "Font" o "XObject"
+	Name   string
+	Object int
+}
+
+// reserve reserva un número de objeto para rellenarlo después con set
+func (d *pdfDocument) reserve() int {
+	d.objects = append(d.objects, nil)
+	return len(d.objects)
+}
+
+// set establece el contenido de un objeto reservado
+func (d *pdfDocument) set(object int, body string) {
+	d.objects[object-1] = []byte(body)
+}
+
+// add añade un objeto y devuelve su número
+func (d *pdfDocument) add(body string) int {
+	object := d.reserve()
+	d.set(object, body)
+	return object
+}
+
+// addStream añade un flujo comprimido con el diccionario indicado (sin /Length ni /Filter)
+func (d *pdfDocument) addStream(dict string, data []byte, compress bool) int {
+	filter := ""
+	if compress {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		data = buf.Bytes()
+		filter = " /Filter /FlateDecode"
+	}
+	return d.add(fmt.Sprintf("<< %s /Length %d%s >>\nstream\n%s\nendstream", dict, len(data), filter, data))
+}
+
+// font devuelve el nombre del recurso de una fuente estándar, añadiéndola si hace falta
+func (d *pdfDocument) font(font standardFont) string {
+	if name, ok := d.fonts[font.Name]; ok {
+		return name
+	}
+	name := fmt.Sprintf("F%d", len(d.fonts)+1)
+	object := d.add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font.Name))
+	d.fonts[font.Name] = name
+	d.resources = append(d.resources, pdfResource{Kind: "Font", Name: name, Object: object})
+	return name
+}
+
+// image devuelve el nombre del recurso de una imagen, añadiéndola si hace falta. Si el
+// formato de la imagen no se puede decodificar (por ejemplo SVG), devuelve "".
+func (d *pdfDocument) image(key string, data []byte) string {
+	if name, ok := d.images[key]; ok {
+		return name
+	}
+	name := ""
+	if object, ok := d.addImage(data); ok {
+		name = fmt.Sprintf("Im%d", len(d.resources)+1)
+		d.resources = append(d.resources, pdfResource{Kind: "XObject", Name: name, Object: object})
+	}
+	d.images[key] = name
+	return name
+}
+
+// addImage añade una imagen como XObject. Los JPEG se incluyen sin volver a
+// comprimir; el resto de formatos se guardan como RGB con su canal alfa.
+func (d *pdfDocument) addImage(data []byte) (int, bool) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, false
+	}
+	if format == "jpeg" && (config.ColorModel == color.YCbCrModel || config.ColorModel == color.GrayModel) {
+		colorSpace := "/DeviceRGB"
+		if config.ColorModel == color.GrayModel {
+			colorSpace = "/DeviceGray"
+		}
+		return d.addStream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode",
+			config.Width, config.Height, colorSpace), data, false), true
+	}
+
+	img, err := decodeImage(data)
+	if err != nil {
+		return 0, false
+	}
+	bounds := img.Bounds()
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	alpha := make([]byte, 0, bounds.Dx()*bounds.Dy())
+	opaque := true
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			rgb = append(rgb, c.R, c.G, c.B)
+			alpha = append(alpha, c.A)
+			opaque = opaque && c.A == 0xff
+		}
+	}
+	mask := ""
+	if !opaque {
+		smask := d.addStream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8",
+			bounds.Dx(), bounds.Dy()), alpha, true)
+		mask = fmt.Sprintf(" /SMask %d 0 R", smask)
+	}
+	return d.addStream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8%s",
+		bounds.Dx(), bounds.Dy(), mask), rgb, true), true
+}
+
+// decodeImage decodifica una imagen PNG, JPEG o GIF
+func decodeImage(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("no se puede decodificar la imagen: %v", err)
+	}
+	return img, nil
+}
+
+// write escribe el documento con la tabla de referencias cruzadas
+func (d *pdfDocument) write(w io.Writer, root, info int) error {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(d.objects))
+	for i, object := range d.objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(d.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.objects)+1, root, info, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// SavePDF escribe la presentación en formato PDF, con una página del tamaño de la
+// diapositiva por cada diapositiva. Se dibujan los fondos, las imágenes y los cuadros
+// de texto con las fuentes estándar de PDF más parecidas a las de cada estilo.
+func (g *ODPGenerator) SavePDF(w io.Writer) error {
+	if err := g.validateClickActions(); err != nil {
+		return err
+	}
+
+	doc := &pdfDocument{fonts: make(map[string]string), images: make(map[string]string)}
+	catalog := doc.reserve()
+	pagesObject := doc.reserve()
+	resources := doc.reserve()
+	pages := make([]int, len(g.Slides))
+	for i := range pages {
+		pages[i] = doc.reserve()
+	}
+
+	pageWidth := g.SlideSize.Width * pointsPerCm
+	pageHeight := g.SlideSize.Height * pointsPerCm
+	for i, slide := range g.Slides {
+		page := &pdfPage{doc: doc, g: g, height: pageHeight, pages: pages, index: i}
+
+		if background := g.slideBackground(slide); background != nil {
+			page.background(*background, pageWidth)
+		}
+		if master := g.slideMaster(slide); master != nil {
+			for _, element := range master.SortedElements() {
+				if err := page.element(element, nil); err != nil {
+					return fmt.Errorf("página maestra %q: %v", master.Name, err)
+				}
+			}
+		}
+		for _, element := range slide.SortedElements() {
+			var action *ClickAction
+			if a, ok := slide.ClickActions[element.ZIndex]; ok {
+				action = &a
+			}
+			if err := page.element(element, action); err != nil {
+				return fmt.Errorf("diapositiva %d: %v", i, err)
+			}
+		}
+
+		content := doc.addStream("", page.content.Bytes(), true)
+		annots := ""
+		if len(page.annots) > 0 {
+			annots = " /Annots [" + strings.Join(page.annots, " ") + "]"
+		}
+		doc.set(pages[i], fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %d 0 R /Contents %d 0 R%s >>",
+			pagesObject, pdfNumber(pageWidth), pdfNumber(pageHeight), resources, content, annots))
+	}
+
+	kids := make([]string, len(pages))
+	for i, page := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	doc.set(pagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	doc.set(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObject))
+
+	var fonts, xobjects []string
+	for _, resource := range doc.resources {
+		entry := fmt.Sprintf("/%s %d 0 R", resource.Name, resource.Object)
+		if resource.Kind == "Font" {
+			fonts = append(fonts, entry)
+		} else {
+			xobjects = append(xobjects, entry)
+		}
+	}
+	doc.set(resources, fmt.Sprintf("<< /ProcSet [/PDF /Text /ImageB /ImageC] /Font << %s >> /XObject << %s >> >>",
+		strings.Join(fonts, " "), strings.Join(xobjects, " ")))
+
+	return doc.write(w, catalog, doc.add(g.pdfInfo()))
+}
+
+// pdfInfo genera el diccionario de información del documento a partir de Metadata
+func (g *ODPGenerator) pdfInfo() string {
+	entries := []string{"/Creator " + pdfText(metaGenerator), "/Producer " + pdfText(metaGenerator)}
+	add := func(key, value string) {
+		if value != "" {
+			entries = append(entries, "/"+key+" "+pdfText(value))
+		}
+	}
+	add("Title", g.Metadata.Title)
+	add("Subject", g.Metadata.Subject)
+	add("Author", g.Metadata.Creator)
+	add("Keywords", strings.Join(g.Metadata.Keywords, ", "))
+	if !g.Metadata.Created.IsZero() {
+		entries = append(entries, "/CreationDate "+pdfDate(g.Metadata.Created))
+	}
+	modified := g.Metadata.Modified
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+	entries = append(entries, "/ModDate "+pdfDate(modified))
+	return "<< " + strings.Join(entries, " ") + " >>"
+}
+
+// pdfPage es una página del PDF en construcción
+type pdfPage struct {
+	doc     *pdfDocument
+	g       *ODPGenerator
+	content bytes.Buffer
+	annots  []string
+	height  float64 // Alto de la página en puntos, para invertir el eje Y
+	pages   []int   // Números de objeto de todas las páginas, para los enlaces
+	index   int
+}
+
+// background dibuja el fondo de la página
+func (p *pdfPage) background(background Background, width float64) {
+	if background.Type == BackgroundImage {
+		if name := p.doc.image(background.Name, background.Data); name != "" {
+			fmt.Fprintf(&p.content, "q %s 0 0 %s 0 0 cm /%s Do Q\n", pdfNumber(width), pdfNumber(p.height), name)
+		}
+		return
+	}
+	if r, g, b, ok := pdfColor(background.Color); ok {
+		fmt.Fprintf(&p.content, "%s %s %s rg 0 0 %s %s re f\n", r, g, b, pdfNumber(width), pdfNumber(p.height))
+	}
+}
+
+// element dibuja un cuadro de texto o una imagen. Los demás elementos no se exportan.
+func (p *pdfPage) element(element DrawableElement, action *ClickAction) error {
+	var x, y, width, height string
+	switch data := element.Data.(type) {
+	case TextBox:
+		x, y, width, height = data.X, data.Y, data.Width, data.Height
+	case Image:
+		x, y, width, height = data.X, data.Y, data.Width, data.Height
+	default:
+		return nil
+	}
+	frame, err := pointsFrame(x, y, width, height)
+	if err != nil {
+		return err
+	}
+
+	switch data := element.Data.(type) {
+	case TextBox:
+		p.textBox(data, frame)
+	case Image:
+		if name := p.doc.image(data.Name, data.Data); name != "" {
+			fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /%s Do Q\n",
+				pdfNumber(frame.Width), pdfNumber(frame.Height), pdfNumber(frame.X), pdfNumber(p.height-frame.Y-frame.Height), name)
+		}
+	}
+	if action != nil {
+		p.link(frame, action)
+	}
+	return nil
+}
+
+// textBox dibuja las líneas de un cuadro de texto
+func (p *pdfPage) textBox(tb TextBox, frame pointRect) {
+	for _, line := range layoutTextBox(tb, frame.Width, frame.Height) {
+		baseline := p.height - frame.Y - line.Baseline
+		for _, fragment := range line.Fragments {
+			font := fontFor(fragment.Style)
+			r, g, b, ok := pdfColor(fragment.Style.Color)
+			if !ok {
+				r, g, b = "0", "0", "0"
+			}
+			fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s %s rg %s Tw 1 0 0 1 %s %s Tm %s Tj ET\n",
+				p.doc.font(font), pdfNumber(fontSizePoints(fragment.Style)), r, g, b, pdfNumber(line.WordSpacing),
+				pdfNumber(frame.X+fragment.X), pdfNumber(baseline), pdfString(fragment.Text))
+			if fragment.Link != "" {
+				p.link(pointRect{
+					X:      frame.X + fragment.X,
+					Y:      frame.Y + line.Baseline - line.Size*fontAscent,
+					Width:  fragment.Width,
+					Height: line.Size * lineSpacing,
+				}, &ClickAction{Type: ActionURL, URL: fragment.Link})
+			}
+		}
+	}
+}
+
+// link añade una anotación de enlace sobre un rectángulo de la página
+func (p *pdfPage) link(rect pointRect, action *ClickAction) {
+	target := ""
+	switch action.Type {
+	case ActionURL:
+		target = "/A << /S /URI /URI " + pdfString(action.URL) + " >>"
+	default:
+		page := -1
+		switch action.Type {
+		case ActionSlide:
+			name, _ := p.g.actionTarget(*action)
+			for i := range p.g.Slides {
+				if p.g.pageName(i) == name {
+					page = i
+				}
+			}
+		case ActionNextSlide:
+			page = p.index + 1
+		case ActionPreviousSlide:
+			page = p.index - 1
+		case ActionFirstSlide:
+			page = 0
+		case ActionLastSlide:
+			page = len(p.pages) - 1
+		}
+		if page < 0 || page >= len(p.pages) {
+			return
+		}
+		target = fmt.Sprintf("/Dest [%d 0 R /Fit]", p.pages[page])
+	}
+	p.annots = append(p.annots, fmt.Sprintf("<< /Type /Annot /Subtype /Link /Rect [%s %s %s %s] /Border [0 0 0] %s >>",
+		pdfNumber(rect.X), pdfNumber(p.height-rect.Y-rect.Height), pdfNumber(rect.X+rect.Width), pdfNumber(p.height-rect.Y), target))
+}
+
+// pointRect es un rectángulo en puntos medido desde la esquina superior izquierda
+type pointRect struct {
+	X, Y, Width, Height float64
+}
+
+// pointsFrame convierte la posición y el tamaño de un elemento a puntos
+func pointsFrame(x, y, width, height string) (pointRect, error) {
+	var rect pointRect
+	values := []*float64{&rect.X, &rect.Y, &rect.Width, &rect.Height}
+	for i, value := range []string{x, y, width, height} {
+		cm, err := parseLength(value)
+		if err != nil {
+			return rect, err
+		}
+		*values[i] = cm * pointsPerCm
+	}
+	return rect, nil
+}
+
+// pdfNumber da formato a un número de PDF con dos decimales como máximo
+func pdfNumber(value float64) string {
+	rounded := math.Round(value*100) / 100
+	if rounded == 0 {
+		rounded = 0 // Evita "-0"
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// pdfColor convierte un color "#RRGGBB" en sus componentes RGB de PDF
+func pdfColor(value string) (r, g, b string, ok bool) {
+	hex := pptxColor(value)
+	if hex == "" {
+		return "", "", "", false
+	}
+	component := func(i int) string {
+		n, _ := strconv.ParseUint(hex[i:i+2], 16, 8)
+		return pdfNumber(float64(n) / 255)
+	}
+	return component(0), component(2), component(4), true
+}
+
+// pdfString codifica un texto como cadena literal de PDF en WinAnsiEncoding
+func pdfString(text string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('(')
+	for _, c := range text {
+		var b byte
+		switch {
+		case c == '\t':
+			b = ' '
+		case c >= 32 && c <= 126, c >= 0xA0 && c <= 0xFF:
+			b = byte(c)
+		default:
+			special, ok := winAnsiSpecial[c]
+			if !ok {
+				special = '?'
+			}
+			b = special
+		}
+		if b == '(' || b == ')' || b == '\\' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(b)
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// pdfText codifica un texto del diccionario de información en UTF-16BE
+func pdfText(text string) string {
+	var sb strings.Builder
+	sb.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&sb, "%04X", unit)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// pdfDate da el formato de fecha de PDF ("(D:20260102150405Z)")
+func pdfDate(t time.Time) string {
+	return "(D:" + t.UTC().Format("20060102150405") + "Z)"
+}
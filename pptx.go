@@ -0,0 +1,742 @@
+package goodp
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// emuPerCm son las unidades EMU de Office Open XML que hay en un centímetro
+const emuPerCm = 360000
+
+// Tipos de relación de los paquetes de Office Open XML
+const (
+	relOfficeDocument = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	relCoreProperties = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"
+	relAppProperties  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties"
+	relSlideMaster    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/slideMaster"
+	relSlideLayout    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/slideLayout"
+	relSlide          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide"
+	relTheme          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme"
+	relPresProps      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/presProps"
+	relViewProps      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/viewProps"
+	relTableStyles    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/tableStyles"
+	relImage          = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+	relHyperlink      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+)
+
+// Tipos de contenido (MIME) de las imágenes, por extensión
+var imageContentTypes = map[string]string{
+	"png":  "image/png",
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
+	"gif":  "image/gif",
+	"bmp":  "image/bmp",
+	"svg":  "image/svg+xml",
+}
+
+// Alineaciones de párrafo de DrawingML equivalentes a las de TextProperties
+var pptxHorizontalAligns = map[string]string{
+	"left":    "l",
+	"start":   "l",
+	"center":  "ctr",
+	"right":   "r",
+	"end":     "r",
+	"justify": "just",
+}
+
+// Anclajes verticales del texto de DrawingML equivalentes a los de TextProperties
+var pptxVerticalAligns = map[string]string{
+	"top":    "t",
+	"middle": "ctr",
+	"center": "ctr",
+	"bottom": "b",
+}
+
+// pptxRel es una relación de una parte del paquete PPTX con otra parte o con una dirección externa
+type pptxRel struct {
+	ID       string
+	Type     string
+	Target   string
+	External bool
+}
+
+// pptxRels es la lista de relaciones de una parte, sin repetir destinos
+type pptxRels struct {
+	Rels []pptxRel
+}
+
+// add añade una relación y devuelve su identificador
+func (r *pptxRels) add(relType, target string, external bool) string {
+	for _, rel := range r.Rels {
+		if rel.Type == relType && rel.Target == target {
+			return rel.ID
+		}
+	}
+	id := fmt.Sprintf("rId%d", len(r.Rels)+1)
+	r.Rels = append(r.Rels, pptxRel{ID: id, Type: relType, Target: target, External: external})
+	return id
+}
+
+// pptxBackground es el fondo de una diapositiva o de la página maestra
+type pptxBackground struct {
+	Color    string // Color RRGGBB
+	ImageRel string // Relación de la imagen de fondo
+}
+
+// pptxRunProps son las propiedades de un fragmento de texto de DrawingML
+type pptxRunProps struct {
+	Size    int // Tamaño en centésimas de punto (0 para el tamaño por defecto)
+	Bold    bool
+	Italic  bool
+	Color   string
+	Font    string
+	LinkRel string
+}
+
+// pptxRun es un fragmento de texto, o un salto de línea si Break es true
+type pptxRun struct {
+	Text  string // Texto escapado
+	Break bool
+	Props pptxRunProps
+}
+
+// pptxParagraph es un párrafo de un cuadro de texto
+type pptxParagraph struct {
+	Align       string
+	MarginLeft  int64
+	MarginRight int64
+	Indent      int64
+	Runs        []pptxRun
+	EndProps    pptxRunProps
+}
+
+// pptxShape es un cuadro de texto o una imagen de una diapositiva, con las medidas en EMU
+type pptxShape struct {
+	ID         int
+	Name       string
+	Picture    bool
+	X, Y       int64
+	Width      int64
+	Height     int64
+	Anchor     string
+	Paragraphs []pptxParagraph
+	ImageRel   string
+	LinkRel    string
+}
+
+// pptxSlide es una diapositiva preparada para generar su parte del paquete
+type pptxSlide struct {
+	Background *pptxBackground
+	Shapes     []pptxShape
+	pptxRels
+}
+
+// pptxMedia es una imagen incluida en ppt/media
+type pptxMedia struct {
+	Name string
+	Data []byte
+}
+
+// pptxPackage contiene todo lo necesario para generar las partes del paquete PPTX
+type pptxPackage struct {
+	Width, Height int64
+	Slides        []pptxSlide
+	Master        pptxSlide
+	Media         []pptxMedia
+	Extensions    []string
+	Metadata      Metadata
+	media         map[string]string // Ruta de la imagen en el modelo -> ruta en ppt/media
+}
+
+// SavePPTX guarda la presentación en formato PowerPoint (.pptx)
+func (g *ODPGenerator) SavePPTX(filename string) error {
+	if !strings.HasSuffix(filename, ".pptx") {
+		filename += ".pptx"
+	}
+
+	data, err := g.SavePPTXStream()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0644)
+}
+
+// SavePPTXStream genera y devuelve los bytes de la presentación en formato PowerPoint
+// (.pptx). Se exportan los cuadros de texto, las imágenes y los fondos, respetando el
+// orden de los Z-index.
+func (g *ODPGenerator) SavePPTXStream() ([]byte, error) {
+	pkg, err := g.pptxPackage()
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New("pptx").Funcs(template.FuncMap{
+		"inc": func(i int) int {
+			return i + 1
+		},
+		"add": func(a, b int) int {
+			return a + b
+		},
+		"date": pptxDate,
+		"contentType": func(extension string) string {
+			return imageContentTypes[extension]
+		},
+		// runProps prepara las propiedades de un fragmento para la etiqueta a:rPr o a:endParaRPr
+		"runProps": func(tag string, props pptxRunProps) map[string]interface{} {
+			return map[string]interface{}{"Tag": tag, "Props": props, "Lang": g.Metadata.Language}
+		},
+	}).Parse(pptxTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	type part struct {
+		name     string
+		template string
+		data     interface{}
+	}
+	presentationRels := &pptxRels{}
+	presentationRels.add(relSlideMaster, "slideMasters/slideMaster1.xml", false)
+	presentationRels.add(relTheme, "theme/theme1.xml", false)
+	presentationRels.add(relPresProps, "presProps.xml", false)
+	presentationRels.add(relViewProps, "viewProps.xml", false)
+	presentationRels.add(relTableStyles, "tableStyles.xml", false)
+	slideRels := make([]string, len(pkg.Slides))
+	for i := range pkg.Slides {
+		slideRels[i] = presentationRels.add(relSlide, fmt.Sprintf("slides/slide%d.xml", i+1), false)
+	}
+	rootRels := &pptxRels{}
+	rootRels.add(relOfficeDocument, "ppt/presentation.xml", false)
+	rootRels.add(relCoreProperties, "docProps/core.xml", false)
+	rootRels.add(relAppProperties, "docProps/app.xml", false)
+	layoutRels := &pptxRels{}
+	layoutRels.add(relSlideMaster, "../slideMasters/slideMaster1.xml", false)
+
+	parts := []part{
+		{"[Content_Types].xml", "contentTypes", pkg},
+		{"_rels/.rels", "rels", rootRels},
+		{"docProps/core.xml", "core", pkg.Metadata},
+		{"docProps/app.xml", "app", pkg},
+		{"ppt/presentation.xml", "presentation", map[string]interface{}{"Package": pkg, "SlideRels": slideRels}},
+		{"ppt/_rels/presentation.xml.rels", "rels", presentationRels},
+		{"ppt/presProps.xml", "presProps", nil},
+		{"ppt/viewProps.xml", "viewProps", nil},
+		{"ppt/tableStyles.xml", "tableStyles", nil},
+		{"ppt/theme/theme1.xml", "theme", nil},
+		{"ppt/slideMasters/slideMaster1.xml", "slideMaster", pkg.Master},
+		{"ppt/slideMasters/_rels/slideMaster1.xml.rels", "rels", pkg.Master.pptxRels},
+		{"ppt/slideLayouts/slideLayout1.xml", "slideLayout", nil},
+		{"ppt/slideLayouts/_rels/slideLayout1.xml.rels", "rels", layoutRels},
+	}
+	for i, slide := range pkg.Slides {
+		parts = append(parts,
+			part{fmt.Sprintf("ppt/slides/slide%d.xml", i+1), "slide", slide},
+			part{fmt.Sprintf("ppt/slides/_rels/slide%d.xml.rels", i+1), "rels", slide.pptxRels})
+	}
+
+	buf := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buf)
+	for _, p := range parts {
+		writer, err := zipWriter.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if err := tmpl.ExecuteTemplate(writer, p.template, p.data); err != nil {
+			return nil, fmt.Errorf("error al generar %s: %v", p.name, err)
+		}
+	}
+	for _, media := range pkg.Media {
+		writer, err := zipWriter.Create(media.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(media.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pptxPackage convierte el modelo de la presentación en las diapositivas, fondos e
+// imágenes del paquete PPTX
+func (g *ODPGenerator) pptxPackage() (*pptxPackage, error) {
+	pkg := &pptxPackage{
+		Width:    cmToEMU(g.SlideSize.Width),
+		Height:   cmToEMU(g.SlideSize.Height),
+		Metadata: g.Metadata,
+		media:    make(map[string]string),
+	}
+
+	if pkg.Metadata.Modified.IsZero() {
+		pkg.Metadata.Modified = time.Now()
+	}
+
+	pkg.Master.add(relSlideLayout, "../slideLayouts/slideLayout1.xml", false)
+	pkg.Master.add(relTheme, "../theme/theme1.xml", false)
+	pkg.Master.Background = pkg.background(&pkg.Master, g.Background)
+
+	for i, slide := range g.Slides {
+		var s pptxSlide
+		s.add(relSlideLayout, "../slideLayouts/slideLayout1.xml", false)
+
+		// El fondo propio tiene preferencia sobre el de la página maestra registrada
+		background := slide.Background
+		if background == nil && slide.MasterPage != "" {
+			if master := g.registeredMaster(slide.MasterPage); master != nil {
+				background = master.Background
+			}
+		}
+		s.Background = pkg.background(&s, background)
+
+		for _, element := range slide.SortedElements() {
+			id := len(s.Shapes) + 2
+			var shape pptxShape
+			var err error
+			switch element.Type {
+			case "textbox":
+				shape, err = pkg.textBoxShape(&s, element.Data.(TextBox))
+			case "image":
+				shape, err = pkg.imageShape(&s, element.Data.(Image))
+			default:
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("diapositiva %d: %v", i, err)
+			}
+			shape.ID = id
+			if action, ok := slide.ClickActions[element.ZIndex]; ok && action.Type == ActionURL {
+				shape.LinkRel = s.add(relHyperlink, action.URL, true)
+			}
+			s.Shapes = append(s.Shapes, shape)
+		}
+		pkg.Slides = append(pkg.Slides, s)
+	}
+	return pkg, nil
+}
+
+// background convierte un fondo del modelo, añadiendo la imagen a la parte que lo usa
+func (pkg *pptxPackage) background(part *pptxSlide, background *Background) *pptxBackground {
+	if background == nil {
+		return nil
+	}
+	if background.Type == BackgroundImage {
+		if len(background.Data) == 0 {
+			return nil
+		}
+		return &pptxBackground{ImageRel: part.add(relImage, "../"+pkg.addMedia(background.Name, background.Data), false)}
+	}
+	color := pptxColor(background.Color)
+	if color == "" {
+		return nil
+	}
+	return &pptxBackground{Color: color}
+}
+
+// addMedia añade una imagen a ppt/media y devuelve su ruta relativa a ppt
+func (pkg *pptxPackage) addMedia(name string, data []byte) string {
+	if media, ok := pkg.media[name]; ok {
+		return media
+	}
+	extension := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	if _, ok := imageContentTypes[extension]; !ok {
+		extension = "png"
+	}
+	media := fmt.Sprintf("media/image%d.%s", len(pkg.Media)+1, extension)
+	pkg.media[name] = media
+	pkg.Media = append(pkg.Media, pptxMedia{Name: "ppt/" + media, Data: data})
+
+	known := false
+	for _, e := range pkg.Extensions {
+		known = known || e == extension
+	}
+	if !known {
+		pkg.Extensions = append(pkg.Extensions, extension)
+	}
+	return media
+}
+
+// textBoxShape convierte un cuadro de texto en una forma de DrawingML
+func (pkg *pptxPackage) textBoxShape(slide *pptxSlide, tb TextBox) (pptxShape, error) {
+	shape, err := pptxFrame(tb.X, tb.Y, tb.Width, tb.Height)
+	if err != nil {
+		return shape, err
+	}
+	props := tb.Props
+	if props == nil {
+		props = NewDefaultTextProperties()
+	}
+	shape.Name = "TextBox"
+	shape.Anchor = pptxVerticalAligns[props.VerticalAlign]
+	if shape.Anchor == "" {
+		shape.Anchor = "t"
+	}
+
+	if len(tb.Paragraphs) == 0 {
+		paragraph := pptxTextParagraph(props)
+		paragraph.EndProps = pptxStyle(tb.Style)
+		paragraph.Runs = pptxRuns(tb.Content, paragraph.EndProps)
+		shape.Paragraphs = []pptxParagraph{paragraph}
+		return shape, nil
+	}
+
+	for _, p := range tb.Paragraphs {
+		paragraphProps := p.Props
+		if paragraphProps == nil {
+			paragraphProps = props
+		}
+		paragraph := pptxTextParagraph(paragraphProps)
+		paragraph.EndProps = pptxStyle(tb.Style)
+		for _, run := range p.Runs {
+			runProps := pptxStyle(run.Style)
+			if run.Link != "" {
+				runProps.LinkRel = slide.add(relHyperlink, run.Link, true)
+			}
+			paragraph.Runs = append(paragraph.Runs, pptxRuns(run.Text, runProps)...)
+		}
+		if len(p.Runs) > 0 {
+			paragraph.EndProps = pptxStyle(p.Runs[len(p.Runs)-1].Style)
+		}
+		shape.Paragraphs = append(shape.Paragraphs, paragraph)
+	}
+	return shape, nil
+}
+
+// imageShape convierte una imagen en una imagen de DrawingML
+func (pkg *pptxPackage) imageShape(slide *pptxSlide, img Image) (pptxShape, error) {
+	shape, err := pptxFrame(img.X, img.Y, img.Width, img.Height)
+	if err != nil {
+		return shape, err
+	}
+	shape.Name = "Picture"
+	shape.Picture = true
+	shape.ImageRel = slide.add(relImage, "../"+pkg.addMedia(img.Name, img.Data), false)
+	return shape, nil
+}
+
+// pptxFrame convierte la posición y el tamaño de un elemento a EMU
+func pptxFrame(x, y, width, height string) (pptxShape, error) {
+	var shape pptxShape
+	values := []*int64{&shape.X, &shape.Y, &shape.Width, &shape.Height}
+	for i, value := range []string{x, y, width, height} {
+		cm, err := parseLength(value)
+		if err != nil {
+			return shape, err
+		}
+		*values[i] = cmToEMU(cm)
+	}
+	return shape, nil
+}
+
+// pptxTextParagraph crea un párrafo con la alineación y las sangrías de props
+func pptxTextParagraph(props *TextProperties) pptxParagraph {
+	return pptxParagraph{
+		Align:       pptxHorizontalAligns[props.HorizontalAlign],
+		MarginLeft:  cmToEMU(props.LeftIndent),
+		MarginRight: cmToEMU(props.RightIndent),
+		Indent:      cmToEMU(props.FirstLineIndent),
+	}
+}
+
+// pptxRuns divide un texto escapado en fragmentos y saltos de línea
+func pptxRuns(text string, props pptxRunProps) []pptxRun {
+	var runs []pptxRun
+	for i, line := range strings.Split(text, "<text:line-break/>") {
+		if i > 0 {
+			runs = append(runs, pptxRun{Break: true, Props: props})
+		}
+		if line != "" {
+			runs = append(runs, pptxRun{Text: line, Props: props})
+		}
+	}
+	return runs
+}
+
+// pptxStyle convierte un estilo de texto en propiedades de fragmento de DrawingML
+func pptxStyle(style TextStyle) pptxRunProps {
+	props := pptxRunProps{
+		Bold:   style.Bold,
+		Italic: style.Italic,
+		Color:  pptxColor(style.Color),
+		Font:   style.FontFamily,
+	}
+	if style.FontSize != "" {
+		// DrawingML usa centésimas de punto
+		props.Size = int(math.Round(fontSizePoints(style) * 100))
+	}
+	return props
+}
+
+// pptxColor convierte un color "#RRGGBB" al formato de DrawingML, o devuelve "" si no es válido
+func pptxColor(color string) string {
+	color = strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(color), "#"))
+	if len(color) != 6 {
+		return ""
+	}
+	for _, c := range color {
+		if !((c >= '0' && c <= '9') || (c >= 'A' && c <= 'F')) {
+			return ""
+		}
+	}
+	return color
+}
+
+// cmToEMU convierte centímetros a EMU
+func cmToEMU(cm float64) int64 {
+	return int64(math.Round(cm * emuPerCm))
+}
+
+// pptxDate da el formato W3CDTF de docProps/core.xml a una fecha
+func pptxDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// pptxTemplates contiene las plantillas de las partes del paquete PPTX
+const pptxTemplates = `
+{{define "contentTypes"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+    <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+    <Default Extension="xml" ContentType="application/xml"/>
+    {{range .Extensions}}
+    <Default Extension="{{.}}" ContentType="{{contentType .}}"/>
+    {{end}}
+    <Override PartName="/ppt/presentation.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.presentation.main+xml"/>
+    <Override PartName="/ppt/slideMasters/slideMaster1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideMaster+xml"/>
+    <Override PartName="/ppt/slideLayouts/slideLayout1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideLayout+xml"/>
+    {{range $index, $slide := .Slides}}
+    <Override PartName="/ppt/slides/slide{{inc $index}}.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slide+xml"/>
+    {{end}}
+    <Override PartName="/ppt/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"/>
+    <Override PartName="/ppt/presProps.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.presProps+xml"/>
+    <Override PartName="/ppt/viewProps.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.viewProps+xml"/>
+    <Override PartName="/ppt/tableStyles.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.tableStyles+xml"/>
+    <Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+    <Override PartName="/docProps/app.xml" ContentType="application/vnd.openxmlformats-officedocument.extended-properties+xml"/>
+</Types>{{end}}
+
+{{define "rels"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+    {{range .Rels}}
+    <Relationship Id="{{.ID}}" Type="{{.Type}}" Target="{{html .Target}}"{{if .External}} TargetMode="External"{{end}}/>
+    {{end}}
+</Relationships>{{end}}
+
+{{define "core"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
+                   xmlns:dc="http://purl.org/dc/elements/1.1/"
+                   xmlns:dcterms="http://purl.org/dc/terms/"
+                   xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+    {{with .Title}}<dc:title>{{html .}}</dc:title>{{end}}
+    {{with .Subject}}<dc:subject>{{html .}}</dc:subject>{{end}}
+    {{with .Creator}}<dc:creator>{{html .}}</dc:creator>{{end}}
+    {{with .Keywords}}<cp:keywords>{{range $i, $k := .}}{{if $i}}, {{end}}{{html $k}}{{end}}</cp:keywords>{{end}}
+    {{with .Description}}<dc:description>{{html .}}</dc:description>{{end}}
+    {{with .Language}}<dc:language>{{html .}}</dc:language>{{end}}
+    {{if not .Created.IsZero}}<dcterms:created xsi:type="dcterms:W3CDTF">{{date .Created}}</dcterms:created>{{end}}
+    {{if not .Modified.IsZero}}<dcterms:modified xsi:type="dcterms:W3CDTF">{{date .Modified}}</dcterms:modified>{{end}}
+</cp:coreProperties>{{end}}
+
+{{define "app"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties">
+    <Application>goodp</Application>
+    <Slides>{{len .Slides}}</Slides>
+</Properties>{{end}}
+
+{{define "presentation"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:presentation xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
+                xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+                xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main">
+    <p:sldMasterIdLst>
+        <p:sldMasterId id="2147483648" r:id="rId1"/>
+    </p:sldMasterIdLst>
+    {{if .SlideRels}}
+    <p:sldIdLst>
+        {{range $index, $rel := .SlideRels}}
+        <p:sldId id="{{add $index 256}}" r:id="{{$rel}}"/>
+        {{end}}
+    </p:sldIdLst>
+    {{end}}
+    <p:sldSz cx="{{.Package.Width}}" cy="{{.Package.Height}}"/>
+    <p:notesSz cx="6858000" cy="9144000"/>
+</p:presentation>{{end}}
+
+{{define "presProps"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:presentationPr xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
+                  xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+                  xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"/>{{end}}
+
+{{define "viewProps"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:viewPr xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
+          xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+          xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"/>{{end}}
+
+{{define "tableStyles"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<a:tblStyleLst xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" def="{5C22544A-7EE6-4342-B048-85BDC9FD1C3A}"/>{{end}}
+
+{{define "theme"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="goodp">
+    <a:themeElements>
+        <a:clrScheme name="goodp">
+            <a:dk1><a:sysClr val="windowText" lastClr="000000"/></a:dk1>
+            <a:lt1><a:sysClr val="window" lastClr="FFFFFF"/></a:lt1>
+            <a:dk2><a:srgbClr val="1F497D"/></a:dk2>
+            <a:lt2><a:srgbClr val="EEECE1"/></a:lt2>
+            <a:accent1><a:srgbClr val="4F81BD"/></a:accent1>
+            <a:accent2><a:srgbClr val="C0504D"/></a:accent2>
+            <a:accent3><a:srgbClr val="9BBB59"/></a:accent3>
+            <a:accent4><a:srgbClr val="8064A2"/></a:accent4>
+            <a:accent5><a:srgbClr val="4BACC6"/></a:accent5>
+            <a:accent6><a:srgbClr val="F79646"/></a:accent6>
+            <a:hlink><a:srgbClr val="0000FF"/></a:hlink>
+            <a:folHlink><a:srgbClr val="800080"/></a:folHlink>
+        </a:clrScheme>
+        <a:fontScheme name="goodp">
+            <a:majorFont><a:latin typeface="Liberation Sans"/><a:ea typeface=""/><a:cs typeface=""/></a:majorFont>
+            <a:minorFont><a:latin typeface="Liberation Sans"/><a:ea typeface=""/><a:cs typeface=""/></a:minorFont>
+        </a:fontScheme>
+        <a:fmtScheme name="goodp">
+            <a:fillStyleLst>
+                <a:solidFill><a:schemeClr val="phClr"/></a:solidFill>
+                <a:solidFill><a:schemeClr val="phClr"/></a:solidFill>
+                <a:solidFill><a:schemeClr val="phClr"/></a:solidFill>
+            </a:fillStyleLst>
+            <a:lnStyleLst>
+                <a:ln w="9525"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:ln>
+                <a:ln w="25400"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:ln>
+                <a:ln w="38100"><a:solidFill><a:schemeClr val="phClr"/></a:solidFill></a:ln>
+            </a:lnStyleLst>
+            <a:effectStyleLst>
+                <a:effectStyle><a:effectLst/></a:effectStyle>
+                <a:effectStyle><a:effectLst/></a:effectStyle>
+                <a:effectStyle><a:effectLst/></a:effectStyle>
+            </a:effectStyleLst>
+            <a:bgFillStyleLst>
+                <a:solidFill><a:schemeClr val="phClr"/></a:solidFill>
+                <a:solidFill><a:schemeClr val="phClr"/></a:solidFill>
+                <a:solidFill><a:schemeClr val="phClr"/></a:solidFill>
+            </a:bgFillStyleLst>
+        </a:fmtScheme>
+    </a:themeElements>
+    <a:objectDefaults/>
+    <a:extraClrSchemeLst/>
+</a:theme>{{end}}
+
+{{define "background"}}
+        {{if .}}
+        <p:bg>
+            <p:bgPr>
+                {{if .ImageRel}}
+                <a:blipFill dpi="0" rotWithShape="1"><a:blip r:embed="{{.ImageRel}}"/><a:srcRect/><a:stretch><a:fillRect/></a:stretch></a:blipFill>
+                {{else}}
+                <a:solidFill><a:srgbClr val="{{.Color}}"/></a:solidFill>
+                {{end}}
+                <a:effectLst/>
+            </p:bgPr>
+        </p:bg>
+        {{end}}
+{{end}}
+
+{{define "groupProperties"}}
+            <p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr>
+            <p:grpSpPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="0" cy="0"/><a:chOff x="0" y="0"/><a:chExt cx="0" cy="0"/></a:xfrm></p:grpSpPr>
+{{end}}
+
+{{define "slideMaster"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sldMaster xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
+             xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+             xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main">
+    <p:cSld>
+        {{if .Background}}{{template "background" .Background}}{{else}}
+        <p:bg><p:bgRef idx="1001"><a:schemeClr val="bg1"/></p:bgRef></p:bg>
+        {{end}}
+        <p:spTree>
+            {{template "groupProperties"}}
+        </p:spTree>
+    </p:cSld>
+    <p:clrMap bg1="lt1" tx1="dk1" bg2="lt2" tx2="dk2" accent1="accent1" accent2="accent2" accent3="accent3" accent4="accent4" accent5="accent5" accent6="accent6" hlink="hlink" folHlink="folHlink"/>
+    <p:sldLayoutIdLst>
+        <p:sldLayoutId id="2147483649" r:id="rId1"/>
+    </p:sldLayoutIdLst>
+</p:sldMaster>{{end}}
+
+{{define "slideLayout"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sldLayout xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
+             xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+             xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"
+             type="blank" preserve="1">
+    <p:cSld name="Blank">
+        <p:spTree>
+            {{template "groupProperties"}}
+        </p:spTree>
+    </p:cSld>
+    <p:clrMapOvr><a:masterClrMapping/></p:clrMapOvr>
+</p:sldLayout>{{end}}
+
+{{define "runProps"}}<a:{{.Tag}}{{with .Lang}} lang="{{html .}}"{{end}}{{with .Props.Size}} sz="{{.}}"{{end}}{{if .Props.Bold}} b="1"{{end}}{{if .Props.Italic}} i="1"{{end}} dirty="0">{{with .Props.Color}}<a:solidFill><a:srgbClr val="{{.}}"/></a:solidFill>{{end}}{{with .Props.Font}}<a:latin typeface="{{html .}}"/>{{end}}{{with .Props.LinkRel}}<a:hlinkClick r:id="{{.}}"/>{{end}}</a:{{.Tag}}>{{end}}
+
+{{define "shape"}}
+            {{if .Picture}}
+            <p:pic>
+                <p:nvPicPr>
+                    <p:cNvPr id="{{.ID}}" name="{{.Name}} {{.ID}}">{{with .LinkRel}}<a:hlinkClick r:id="{{.}}"/>{{end}}</p:cNvPr>
+                    <p:cNvPicPr><a:picLocks noChangeAspect="1"/></p:cNvPicPr>
+                    <p:nvPr/>
+                </p:nvPicPr>
+                <p:blipFill><a:blip r:embed="{{.ImageRel}}"/><a:stretch><a:fillRect/></a:stretch></p:blipFill>
+                <p:spPr>
+                    <a:xfrm><a:off x="{{.X}}" y="{{.Y}}"/><a:ext cx="{{.Width}}" cy="{{.Height}}"/></a:xfrm>
+                    <a:prstGeom prst="rect"><a:avLst/></a:prstGeom>
+                </p:spPr>
+            </p:pic>
+            {{else}}
+            <p:sp>
+                <p:nvSpPr>
+                    <p:cNvPr id="{{.ID}}" name="{{.Name}} {{.ID}}">{{with .LinkRel}}<a:hlinkClick r:id="{{.}}"/>{{end}}</p:cNvPr>
+                    <p:cNvSpPr txBox="1"/>
+                    <p:nvPr/>
+                </p:nvSpPr>
+                <p:spPr>
+                    <a:xfrm><a:off x="{{.X}}" y="{{.Y}}"/><a:ext cx="{{.Width}}" cy="{{.Height}}"/></a:xfrm>
+                    <a:prstGeom prst="rect"><a:avLst/></a:prstGeom>
+                    <a:noFill/>
+                </p:spPr>
+                <p:txBody>
+                    <a:bodyPr wrap="square" rtlCol="0" anchor="{{.Anchor}}"><a:noAutofit/></a:bodyPr>
+                    <a:lstStyle/>
+                    {{range .Paragraphs}}
+                    <a:p>
+                        <a:pPr{{with .Align}} algn="{{.}}"{{end}}{{with .MarginLeft}} marL="{{.}}"{{end}}{{with .MarginRight}} marR="{{.}}"{{end}}{{with .Indent}} indent="{{.}}"{{end}}/>
+                        {{range .Runs}}{{if .Break}}<a:br>{{template "runProps" (runProps "rPr" .Props)}}</a:br>{{else}}<a:r>{{template "runProps" (runProps "rPr" .Props)}}<a:t>{{.Text}}</a:t></a:r>{{end}}{{end}}
+                        {{template "runProps" (runProps "endParaRPr" .EndProps)}}
+                    </a:p>
+                    {{end}}
+                </p:txBody>
+            </p:sp>
+            {{end}}
+{{end}}
+
+{{define "slide"}}<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sld xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"
+       xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+       xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main">
+    <p:cSld>
+        {{template "background" .Background}}
+        <p:spTree>
+            {{template "groupProperties"}}
+            {{range .Shapes}}{{template "shape" .}}{{end}}
+        </p:spTree>
+    </p:cSld>
+    <p:clrMapOvr><a:masterClrMapping/></p:clrMapOvr>
+</p:sld>{{end}}
+`
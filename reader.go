@@ -0,0 +1,1767 @@
+package goodp
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Espacios de nombres de OpenDocument usados al leer presentaciones
+const (
+	nsOffice       = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	nsStyle        = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+	nsText         = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	nsDraw         = "urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"
+	nsFo           = "urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"
+	nsSvg          = "urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0"
+	nsTable        = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	nsChart        = "urn:oasis:names:tc:opendocument:xmlns:chart:1.0"
+	nsSmil         = "urn:oasis:names:tc:opendocument:xmlns:smil-compatible:1.0"
+	nsAnim         = "urn:oasis:names:tc:opendocument:xmlns:animation:1.0"
+	nsXML          = "http://www.w3.org/XML/1998/namespace"
+	nsScript       = "urn:oasis:names:tc:opendocument:xmlns:script:1.0"
+	nsXlink        = "http://www.w3.org/1999/xlink"
+	nsMeta         = "urn:oasis:names:tc:opendocument:xmlns:meta:1.0"
+	nsDC           = "http://purl.org/dc/elements/1.1/"
+	nsPresentation = "urn:oasis:names:tc:opendocument:xmlns:presentation:1.0"
+)
+
+const mimetypePresentation = "application/vnd.oasis.opendocument.presentation"
+
+// Open abre un archivo .odp existente y lo carga en un ODPGenerator para poder
+// modificarlo y volver a guardarlo
+func Open(filename string) (*ODPGenerator, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return Read(file, info.Size())
+}
+
+// Load carga una presentación a partir de los bytes de un archivo ODP
+// (por ejemplo, los devueltos por SaveStream)
+func Load(data []byte) (*ODPGenerator, error) {
+	return Read(bytes.NewReader(data), int64(len(data)))
+}
+
+// Read lee una presentación ODP desde r, que debe contener size bytes.
+// Se interpretan content.xml, styles.xml y las imágenes de Pictures/ y media/
+// para reconstruir las diapositivas, páginas maestras, cuadros de texto, imágenes y fondos.
+func Read(r io.ReaderAt, size int64) (*ODPGenerator, error) {
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir el paquete ODP: %w", err)
+	}
+
+	reader, err := newODPReader(zipReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader.generator()
+}
+
+// odpReader contiene el estado necesario para convertir un paquete ODP en un ODPGenerator
+type odpReader struct {
+	files      map[string]*zip.File
+	content    *xmlNode
+	styles     *xmlNode
+	styleIndex map[string]*xmlNode // familia + "/" + nombre del estilo
+	fontFaces  map[string]string   // nombre de la fuente -> familia
+	fillImages map[string]string   // nombre del relleno -> ruta de la imagen
+	pageNames  map[string]int      // nombre de la página -> índice de la diapositiva
+	// Declaraciones de pies de página (texto) y fechas, por nombre
+	footerDecls   map[string]string
+	dateTimeDecls map[string]*xmlNode
+}
+
+func newODPReader(zipReader *zip.Reader) (*odpReader, error) {
+	r := &odpReader{
+		files:      make(map[string]*zip.File),
+		styleIndex: make(map[string]*xmlNode),
+		fontFaces:  make(map[string]string),
+		fillImages: make(map[string]string),
+	}
+	for _, f := range zipReader.File {
+		r.files[f.Name] = f
+	}
+
+	// Validar el tipo de documento si el paquete incluye el mimetype
+	if _, ok := r.files["mimetype"]; ok {
+		mimetype, err := r.readFile("mimetype")
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(string(mimetype)) != mimetypePresentation {
+			return nil, fmt.Errorf("el archivo no es una presentación ODP: %s", mimetype)
+		}
+	}
+
+	contentData, err := r.readFile("content.xml")
+	if err != nil {
+		return nil, err
+	}
+	r.content, err = parseXMLTree(contentData)
+	if err != nil {
+		return nil, fmt.Errorf("content.xml inválido: %w", err)
+	}
+
+	// styles.xml es opcional en el paquete
+	if _, ok := r.files["styles.xml"]; ok {
+		stylesData, err := r.readFile("styles.xml")
+		if err != nil {
+			return nil, err
+		}
+		r.styles, err = parseXMLTree(stylesData)
+		if err != nil {
+			return nil, fmt.Errorf("styles.xml inválido: %w", err)
+		}
+	}
+
+	for _, doc := range []*xmlNode{r.styles, r.content} {
+		r.indexStyles(doc)
+	}
+
+	return r, nil
+}
+
+// readFile devuelve el contenido de una entrada del paquete
+func (r *odpReader) readFile(name string) ([]byte, error) {
+	f, ok := r.files[strings.TrimPrefix(name, "./")]
+	if !ok {
+		return nil, fmt.Errorf("el paquete ODP no contiene %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// indexStyles registra los estilos, fuentes y rellenos declarados en un documento
+func (r *odpReader) indexStyles(doc *xmlNode) {
+	if doc == nil {
+		return
+	}
+
+	if decls := doc.child(nsOffice, "font-face-decls"); decls != nil {
+		for _, face := range decls.children(nsStyle, "font-face") {
+			family := strings.Trim(face.attr(nsSvg, "font-family"), "'\"")
+			r.fontFaces[face.attr(nsStyle, "name")] = family
+		}
+	}
+
+	for _, section := range []string{"styles", "automatic-styles"} {
+		container := doc.child(nsOffice, section)
+		if container == nil {
+			continue
+		}
+		for _, node := range container.Children {
+			switch {
+			case node.is(nsStyle, "style"):
+				key := node.attr(nsStyle, "family") + "/" + node.attr(nsStyle, "name")
+				r.styleIndex[key] = node
+			case node.is(nsStyle, "page-layout"):
+				r.styleIndex["page-layout/"+node.attr(nsStyle, "name")] = node
+			case node.is(nsText, "list-style"):
+				r.styleIndex["list/"+node.attr(nsStyle, "name")] = node
+			case node.is(nsDraw, "fill-image"):
+				r.fillImages[node.attr(nsDraw, "name")] = node.attr(nsXlink, "href")
+			}
+		}
+	}
+}
+
+// style busca un estilo por familia y nombre
+func (r *odpReader) style(family, name string) *xmlNode {
+	if name == "" {
+		return nil
+	}
+	return r.styleIndex[family+"/"+name]
+}
+
+// masterPage busca una página maestra por nombre (o la primera si name está vacío)
+func (r *odpReader) masterPage(name string) *xmlNode {
+	if r.styles == nil {
+		return nil
+	}
+	masters := r.styles.child(nsOffice, "master-styles")
+	if masters == nil {
+		return nil
+	}
+	pages := masters.children(nsStyle, "master-page")
+	for _, page := range pages {
+		if name == "" || page.attr(nsStyle, "name") == name {
+			return page
+		}
+	}
+	return nil
+}
+
+// generator construye el ODPGenerator a partir de los documentos leídos
+func (r *odpReader) generator() (*ODPGenerator, error) {
+	g := New()
+
+	presentation := r.content.find(nsOffice, "presentation")
+	if presentation == nil {
+		return nil, fmt.Errorf("content.xml no contiene una presentación")
+	}
+	pages := presentation.children(nsDraw, "page")
+
+	// El tamaño y el fondo global se obtienen de la página maestra de la primera diapositiva
+	masterName := ""
+	if len(pages) > 0 {
+		masterName = pages[0].attr(nsDraw, "master-page-name")
+	}
+	if master := r.masterPage(masterName); master != nil {
+		if size, ok := r.pageSize(master); ok {
+			g.SlideSize = size
+		}
+	}
+
+	// Las páginas maestras con contenido propio se conservan; el fondo de la página
+	// "Default" que genera goodp es el fondo global de la presentación
+	g.MasterPages = r.masterPages()
+	if master := r.masterPage("Default"); master != nil && g.registeredMaster("Default") == nil {
+		g.Background = r.masterBackground(master)
+	}
+
+	g.Metadata = r.metadata()
+
+	r.pageNames = make(map[string]int)
+	for i, page := range pages {
+		r.pageNames[page.attr(nsDraw, "name")] = i
+	}
+
+	r.footerDecls = make(map[string]string)
+	r.dateTimeDecls = make(map[string]*xmlNode)
+	for _, decl := range presentation.children(nsPresentation, "footer-decl") {
+		r.footerDecls[decl.attr(nsPresentation, "name")] = decl.text()
+	}
+	for _, decl := range presentation.children(nsPresentation, "date-time-decl") {
+		r.dateTimeDecls[decl.attr(nsPresentation, "name")] = decl
+	}
+
+	for _, page := range pages {
+		slide, err := r.slide(g, page)
+		if err != nil {
+			return nil, err
+		}
+		g.Slides = append(g.Slides, slide)
+	}
+	collapseHeaderFooters(g)
+
+	return g, nil
+}
+
+// pageSize obtiene el tamaño de página definido en la disposición de una página maestra
+func (r *odpReader) pageSize(master *xmlNode) (SlideSize, bool) {
+	layout := r.style("page-layout", master.attr(nsStyle, "page-layout-name"))
+	if layout == nil {
+		return SlideSize{}, false
+	}
+	props := layout.child(nsStyle, "page-layout-properties")
+	if props == nil {
+		return SlideSize{}, false
+	}
+	width, errW := parseLength(props.attr(nsFo, "page-width"))
+	height, errH := parseLength(props.attr(nsFo, "page-height"))
+	if errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return SlideSize{}, false
+	}
+	return SlideSize{Width: width, Height: height}, true
+}
+
+// masterBackground obtiene el fondo de una página maestra, ya sea a través de su
+// estilo de página o de unas propiedades declaradas directamente en ella
+func (r *odpReader) masterBackground(master *xmlNode) *Background {
+	if props := master.child(nsStyle, "drawing-page-properties"); props != nil {
+		if bg := r.backgroundFromProperties(props); bg != nil {
+			return bg
+		}
+	}
+	return r.pageBackground(master.attr(nsDraw, "style-name"))
+}
+
+// masterPages lee las páginas maestras de styles.xml con su fondo, sus textos, sus
+// imágenes y sus marcadores de posición. La página "Default" sin más contenido que
+// los marcadores del pie de página es la que genera goodp y no se registra.
+func (r *odpReader) masterPages() []*MasterPage {
+	if r.styles == nil {
+		return nil
+	}
+	container := r.styles.child(nsOffice, "master-styles")
+	if container == nil {
+		return nil
+	}
+
+	var masters []*MasterPage
+	for _, node := range container.children(nsStyle, "master-page") {
+		master := &MasterPage{
+			Name:       node.attr(nsStyle, "name"),
+			Background: r.masterBackground(node),
+			index:      len(masters),
+		}
+		if master.Name == "" {
+			continue
+		}
+
+		footerOnly := true
+		for _, frame := range flattenGroups(node) {
+			if !frame.is(nsDraw, "frame") {
+				continue
+			}
+			zIndex := master.lastZIndex + 1
+			if z, err := strconv.Atoi(frame.attr(nsDraw, "z-index")); err == nil {
+				zIndex = z
+			}
+
+			switch {
+			case frame.attr(nsPresentation, "placeholder") == "true":
+				class := PlaceholderClass(frame.attr(nsPresentation, "class"))
+				x, y, width, height := frameGeometry(frame)
+				master.Placeholders = append(master.Placeholders, Placeholder{
+					Class:  class,
+					X:      x,
+					Y:      y,
+					Width:  width,
+					Height: height,
+				})
+				switch class {
+				case PlaceholderHeader, PlaceholderFooter, PlaceholderDateTime, PlaceholderPageNumber:
+				default:
+					footerOnly = false
+				}
+			case frame.child(nsDraw, "text-box") != nil:
+				textBox, ok := r.textBox(frame)
+				if !ok {
+					continue
+				}
+				// El campo del número de diapositiva se lee como texto
+				if frame.find(nsText, "page-number") != nil {
+					textBox.Content = slideNumberField
+					textBox.Paragraphs = nil
+				}
+				textBox.ZIndex = master.getNextZIndex(zIndex)
+				master.TextBoxes = append(master.TextBoxes, &textBox)
+				footerOnly = false
+			case frame.child(nsDraw, "image") != nil:
+				image, ok := r.image(frame)
+				if !ok {
+					continue
+				}
+				image.ZIndex = master.getNextZIndex(zIndex)
+				master.Images = append(master.Images, &image)
+				footerOnly = false
+			}
+		}
+
+		if master.Name == "Default" && footerOnly {
+			continue
+		}
+		masters = append(masters, master)
+	}
+	return masters
+}
+
+// pageBackground obtiene el fondo definido en un estilo de página (familia drawing-page)
+func (r *odpReader) pageBackground(styleName string) *Background {
+	style := r.style("drawing-page", styleName)
+	if style == nil {
+		return nil
+	}
+	props := style.child(nsStyle, "drawing-page-properties")
+	if props == nil {
+		return nil
+	}
+	return r.backgroundFromProperties(props)
+}
+
+func (r *odpReader) backgroundFromProperties(props *xmlNode) *Background {
+	switch props.attr(nsDraw, "fill") {
+	case "bitmap":
+		href, ok := r.fillImages[props.attr(nsDraw, "fill-image-name")]
+		if !ok {
+			return nil
+		}
+		data, err := r.readFile(href)
+		if err != nil || len(data) == 0 {
+			return nil
+		}
+		return &Background{
+			Type: BackgroundImage,
+			Data: data,
+			Name: strings.TrimPrefix(href, "./"),
+		}
+	case "solid":
+		color := props.attr(nsDraw, "fill-color")
+		if color == "" {
+			return nil
+		}
+		return &Background{
+			Type:  BackgroundColor,
+			Color: color,
+		}
+	}
+	return nil
+}
+
+// slide convierte un elemento draw:page en una diapositiva
+func (r *odpReader) slide(g *ODPGenerator, page *xmlNode) (Slide, error) {
+	slide := Slide{}
+	if name := page.attr(nsDraw, "name"); name != "" && !isGeneratedPageName(name) && r.pageNames[name] == len(g.Slides) {
+		slide.ID = name
+	}
+
+	if name := page.attr(nsDraw, "master-page-name"); g.registeredMaster(name) != nil {
+		slide.MasterPage = name
+	}
+
+	styleName := page.attr(nsDraw, "style-name")
+	if bg := r.pageBackground(styleName); bg != nil {
+		// "backgroundStyle" es el estilo que usa goodp para el fondo global. Los estilos
+		// "slidePageN" también lo usan si no existe el estilo "slideBackgroundN".
+		global := styleName == "backgroundStyle"
+		if index := strings.TrimPrefix(styleName, "slidePage"); index != styleName {
+			global = r.style("drawing-page", "slideBackground"+index) == nil
+		}
+		switch {
+		case global && g.Background == nil:
+			g.Background = bg
+		case global || sameBackground(bg, g.Background):
+			// El fondo ya es el global de la presentación
+		default:
+			slide.Background = bg
+		}
+	}
+
+	// Z-index de los elementos y párrafos con identificador, a los que se refieren las animaciones
+	ids := make(map[string]int)
+	paragraphIDs := make(map[string]int)
+	actions := make(map[int]ClickAction)
+	for _, frame := range flattenGroups(page) {
+		zIndex := slide.lastZIndex + 1
+		if z, err := strconv.Atoi(frame.attr(nsDraw, "z-index")); err == nil {
+			zIndex = z
+		}
+		if id := elementXMLID(frame); id != "" {
+			ids[id] = zIndex
+		}
+		for _, id := range paragraphXMLIDs(frame) {
+			paragraphIDs[id] = zIndex
+		}
+		if action, ok := r.clickAction(frame); ok {
+			actions[zIndex] = action
+		}
+
+		if frame.Name.Space == nsDraw && isShapeElement(frame.Name.Local) {
+			shape, ok := r.shape(frame)
+			if !ok {
+				continue
+			}
+			shape.ZIndex = slide.getNextZIndex(zIndex)
+			slide.Shapes = append(slide.Shapes, &shape)
+			continue
+		}
+		if !frame.is(nsDraw, "frame") {
+			continue
+		}
+
+		switch {
+		case frame.child(nsDraw, "text-box") != nil && frame.child(nsDraw, "text-box").child(nsText, "list") != nil:
+			list, ok := r.list(frame)
+			if !ok {
+				continue
+			}
+			list.ZIndex = slide.getNextZIndex(zIndex)
+			slide.Lists = append(slide.Lists, &list)
+		case frame.child(nsDraw, "text-box") != nil:
+			textBox, ok := r.textBox(frame)
+			if !ok {
+				continue
+			}
+			textBox.ZIndex = slide.getNextZIndex(zIndex)
+			slide.TextBoxes = append(slide.TextBoxes, &textBox)
+		case frame.child(nsDraw, "object") != nil:
+			chart, ok := r.chart(frame)
+			if !ok {
+				continue
+			}
+			chart.ZIndex = slide.getNextZIndex(zIndex)
+			slide.Charts = append(slide.Charts, &chart)
+		case frame.child(nsTable, "table") != nil:
+			table, ok := r.table(frame)
+			if !ok {
+				continue
+			}
+			table.ZIndex = slide.getNextZIndex(zIndex)
+			slide.Tables = append(slide.Tables, &table)
+		case frame.child(nsDraw, "image") != nil:
+			image, ok := r.image(frame)
+			if !ok {
+				continue
+			}
+			image.ZIndex = slide.getNextZIndex(zIndex)
+			slide.Images = append(slide.Images, &image)
+		}
+	}
+
+	slide.Transition = r.transition(styleName)
+	slide.HeaderFooter = r.headerFooter(page, styleName)
+	slide.Animations = readAnimations(page, ids, paragraphIDs, &slide)
+	for zIndex, action := range actions {
+		if !slide.hasElement(zIndex) {
+			continue
+		}
+		if slide.ClickActions == nil {
+			slide.ClickActions = make(map[int]ClickAction)
+		}
+		slide.ClickActions[zIndex] = action
+	}
+
+	if notes := page.child(nsPresentation, "notes"); notes != nil {
+		slide.Notes = r.notes(notes)
+	}
+
+	// Asegurar que los nuevos elementos queden por encima de los existentes
+	for _, tb := range slide.TextBoxes {
+		if tb.ZIndex > slide.lastZIndex {
+			slide.lastZIndex = tb.ZIndex
+		}
+	}
+	for _, img := range slide.Images {
+		if img.ZIndex > slide.lastZIndex {
+			slide.lastZIndex = img.ZIndex
+		}
+	}
+	for _, list := range slide.Lists {
+		if list.ZIndex > slide.lastZIndex {
+			slide.lastZIndex = list.ZIndex
+		}
+	}
+	for _, table := range slide.Tables {
+		if table.ZIndex > slide.lastZIndex {
+			slide.lastZIndex = table.ZIndex
+		}
+	}
+	for _, shape := range slide.Shapes {
+		if shape.ZIndex > slide.lastZIndex {
+			slide.lastZIndex = shape.ZIndex
+		}
+	}
+	for _, chart := range slide.Charts {
+		if chart.ZIndex > slide.lastZIndex {
+			slide.lastZIndex = chart.ZIndex
+		}
+	}
+
+	return slide, nil
+}
+
+// flattenGroups devuelve los elementos de dibujo de una página, incluyendo los
+// que están dentro de grupos (draw:g), cuyas posiciones ya son absolutas
+func flattenGroups(parent *xmlNode) []*xmlNode {
+	var nodes []*xmlNode
+	for _, node := range parent.Children {
+		if node.is(nsDraw, "g") {
+			nodes = append(nodes, flattenGroups(node)...)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// frameGeometry lee la posición y el tamaño de un draw:frame en centímetros
+func frameGeometry(frame *xmlNode) (x, y, width, height string) {
+	format := func(value string) string {
+		cm, err := parseLength(value)
+		if err != nil {
+			return "0.00cm"
+		}
+		return fmt.Sprintf("%.2fcm", cm)
+	}
+	return format(frame.attr(nsSvg, "x")),
+		format(frame.attr(nsSvg, "y")),
+		format(frame.attr(nsSvg, "width")),
+		format(frame.attr(nsSvg, "height"))
+}
+
+// textBox convierte un draw:frame con draw:text-box en un TextBox. Si todo el texto
+// usa el mismo estilo se guarda en Content; si no, como párrafos enriquecidos.
+func (r *odpReader) textBox(frame *xmlNode) (TextBox, bool) {
+	box := frame.child(nsDraw, "text-box")
+	var paragraphs []*xmlNode
+	for _, node := range box.Children {
+		if node.is(nsText, "p") || node.is(nsText, "h") {
+			paragraphs = append(paragraphs, node)
+		}
+	}
+
+	// Los marcadores de posición vacíos no tienen contenido que conservar
+	if frame.attr(nsPresentation, "placeholder") == "true" || len(paragraphs) == 0 {
+		return TextBox{}, false
+	}
+
+	x, y, width, height := frameGeometry(frame)
+	textBox := TextBox{
+		X:      x,
+		Y:      y,
+		Width:  width,
+		Height: height,
+		Props:  r.paragraphProperties(paragraphs[0]),
+	}
+	textBox.Props.VerticalAlign = r.verticalAlign(frame)
+
+	rich := make([]Paragraph, 0, len(paragraphs))
+	styles := make(map[string]bool)
+	sameProps := true
+	links := false // los enlaces solo se conservan como texto enriquecido
+	for i, p := range paragraphs {
+		paragraph := Paragraph{Runs: r.paragraphRuns(p)}
+		if i > 0 {
+			props := r.paragraphProperties(p)
+			props.VerticalAlign = textBox.Props.VerticalAlign
+			if *props != *textBox.Props {
+				paragraph.Props = props
+				sameProps = false
+			}
+		}
+		for _, run := range paragraph.Runs {
+			styles[generateStyleName(run.Style)] = true
+			links = links || run.Link != ""
+		}
+		rich = append(rich, paragraph)
+	}
+
+	if len(styles) > 1 || !sameProps || links {
+		for i := range rich {
+			for j := range rich[i].Runs {
+				rich[i].Runs[j].Text = escapeXML(rich[i].Runs[j].Text)
+			}
+		}
+		textBox.Paragraphs = rich
+		textBox.Style = r.textStyle(paragraphs[0])
+		return textBox, true
+	}
+
+	// Todo el texto tiene el mismo estilo
+	lines := make([]string, 0, len(rich))
+	for _, p := range rich {
+		var sb strings.Builder
+		for _, run := range p.Runs {
+			sb.WriteString(run.Text)
+		}
+		lines = append(lines, sb.String())
+	}
+	textBox.Content = escapeXML(strings.Join(lines, "\n"))
+	textBox.Style = r.textStyle(paragraphs[0])
+	return textBox, true
+}
+
+// textStyle obtiene el estilo de texto del primer fragmento de un párrafo,
+// completándolo con las propiedades de texto del propio párrafo
+func (r *odpReader) textStyle(p *xmlNode) TextStyle {
+	if runs := r.paragraphRuns(p); len(runs) > 0 {
+		return runs[0].Style
+	}
+	var style TextStyle
+	r.applyTextProperties(&style, r.style("paragraph", p.attr(nsText, "style-name")))
+	return style
+}
+
+// applyTextProperties completa los campos vacíos de style con las propiedades de texto del estilo dado
+func (r *odpReader) applyTextProperties(style *TextStyle, node *xmlNode) {
+	if node == nil {
+		return
+	}
+	props := node.child(nsStyle, "text-properties")
+	if props == nil {
+		return
+	}
+
+	if style.FontSize == "" {
+		style.FontSize = props.attr(nsFo, "font-size")
+	}
+	if style.FontFamily == "" {
+		style.FontFamily = strings.Trim(props.attr(nsFo, "font-family"), "'\"")
+		if style.FontFamily == "" {
+			style.FontFamily = r.fontFaces[props.attr(nsStyle, "font-name")]
+		}
+	}
+	if style.Color == "" {
+		style.Color = props.attr(nsFo, "color")
+	}
+	if weight := props.attr(nsFo, "font-weight"); weight == "bold" || weight == "700" ||
+		weight == "800" || weight == "900" {
+		style.Bold = true
+	}
+	if fontStyle := props.attr(nsFo, "font-style"); fontStyle == "italic" || fontStyle == "oblique" {
+		style.Italic = true
+	}
+}
+
+// paragraphProperties obtiene la alineación horizontal y las sangrías de un párrafo
+func (r *odpReader) paragraphProperties(p *xmlNode) *TextProperties {
+	props := NewDefaultTextProperties()
+
+	style := r.style("paragraph", p.attr(nsText, "style-name"))
+	if style == nil {
+		return props
+	}
+	para := style.child(nsStyle, "paragraph-properties")
+	if para == nil {
+		return props
+	}
+
+	switch align := para.attr(nsFo, "text-align"); align {
+	case "start":
+		props.HorizontalAlign = "left"
+	case "end":
+		props.HorizontalAlign = "right"
+	case "left", "right", "center", "justify":
+		props.HorizontalAlign = align
+	}
+	props.LeftIndent, _ = parseLength(para.attr(nsFo, "margin-left"))
+	props.RightIndent, _ = parseLength(para.attr(nsFo, "margin-right"))
+	props.FirstLineIndent, _ = parseLength(para.attr(nsFo, "text-indent"))
+	return props
+}
+
+// verticalAlign obtiene la alineación vertical del texto de un marco, que puede
+// estar en su estilo gráfico o en su estilo de presentación
+func (r *odpReader) verticalAlign(frame *xmlNode) string {
+	align := "top"
+	styles := []*xmlNode{
+		r.style("graphic", frame.attr(nsDraw, "style-name")),
+		r.style("presentation", frame.attr(nsPresentation, "style-name")),
+	}
+	for _, style := range styles {
+		if style == nil {
+			continue
+		}
+		graphic := style.child(nsStyle, "graphic-properties")
+		if graphic == nil {
+			continue
+		}
+		switch value := graphic.attr(nsDraw, "textarea-vertical-align"); value {
+		case "top", "middle", "bottom":
+			align = value
+		}
+	}
+	return align
+}
+
+// list convierte un draw:frame cuyo cuadro de texto contiene un text:list en una List
+func (r *odpReader) list(frame *xmlNode) (List, bool) {
+	node := frame.child(nsDraw, "text-box").child(nsText, "list")
+	items := r.listItems(node)
+	if len(items) == 0 {
+		return List{}, false
+	}
+
+	x, y, width, height := frameGeometry(frame)
+	list := List{
+		Items:  items,
+		X:      x,
+		Y:      y,
+		Width:  width,
+		Height: height,
+		Props:  NewDefaultTextProperties(),
+	}
+	list.Props.VerticalAlign = r.verticalAlign(frame)
+
+	if p := node.find(nsText, "p"); p != nil {
+		list.Style = r.textStyle(p)
+		list.Props.HorizontalAlign = r.paragraphProperties(p).HorizontalAlign
+	}
+
+	// Formato de los niveles según el estilo de lista
+	if style := r.style("list", node.attr(nsText, "style-name")); style != nil {
+		depth := listDepth(items)
+		for _, levelNode := range style.Children {
+			level, err := strconv.Atoi(levelNode.attr(nsText, "level"))
+			if err != nil || level < 1 || level > depth {
+				continue
+			}
+			for len(list.Levels) < level {
+				list.Levels = append(list.Levels, ListLevel{BulletChar: defaultBullets[0]})
+			}
+			list.Levels[level-1] = r.listLevel(levelNode)
+		}
+		list.Ordered = len(list.Levels) > 0 && list.Levels[0].Numbering != ""
+	}
+
+	return list, true
+}
+
+// listItems lee los elementos de un text:list y sus sublistas
+func (r *odpReader) listItems(node *xmlNode) []ListItem {
+	var items []ListItem
+	for _, itemNode := range node.children(nsText, "list-item") {
+		var item ListItem
+		if p := itemNode.child(nsText, "p"); p != nil {
+			item.Runs = r.paragraphRuns(p)
+			for i := range item.Runs {
+				item.Runs[i].Text = escapeXML(item.Runs[i].Text)
+			}
+		}
+		for _, sublist := range itemNode.children(nsText, "list") {
+			item.Items = append(item.Items, r.listItems(sublist)...)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// listLevel convierte la definición de un nivel de un estilo de lista en un ListLevel
+func (r *odpReader) listLevel(node *xmlNode) ListLevel {
+	switch {
+	case node.is(nsText, "list-level-style-number"):
+		format := node.attr(nsStyle, "num-format")
+		if format == "" {
+			format = "1"
+		}
+		return ListLevel{Numbering: node.attr(nsStyle, "num-prefix") + format + node.attr(nsStyle, "num-suffix")}
+	case node.is(nsText, "list-level-style-image"):
+		href := strings.TrimPrefix(node.attr(nsXlink, "href"), "./")
+		if data, err := r.readFile(href); err == nil && len(data) > 0 {
+			return ListLevel{
+				BulletImage:          data,
+				BulletImageExtension: path.Ext(href),
+				BulletImageName:      href,
+			}
+		}
+	case node.is(nsText, "list-level-style-bullet"):
+		if bullet := node.attr(nsText, "bullet-char"); bullet != "" {
+			return ListLevel{BulletChar: bullet}
+		}
+	}
+	return ListLevel{BulletChar: defaultBullets[0]}
+}
+
+// table convierte un draw:frame con table:table en una Table
+func (r *odpReader) table(frame *xmlNode) (Table, bool) {
+	node := frame.child(nsTable, "table")
+	x, y, width, height := frameGeometry(frame)
+	table := Table{
+		HeaderRow: node.attr(nsTable, "use-first-row-styles") == "true",
+		X:         x,
+		Y:         y,
+		Width:     width,
+		Height:    height,
+	}
+
+	// Filas y celdas (las celdas cubiertas por una combinación se conservan como tales)
+	var rowHeight float64
+	for _, rowNode := range node.children(nsTable, "table-row") {
+		var row []TableCell
+		for _, cellNode := range rowNode.Children {
+			switch {
+			case cellNode.is(nsTable, "covered-table-cell"):
+				row = append(row, TableCell{Covered: true, RowSpan: 1, ColSpan: 1})
+			case cellNode.is(nsTable, "table-cell"):
+				row = append(row, r.tableCell(cellNode))
+			}
+		}
+		table.Rows = append(table.Rows, row)
+
+		if rowHeight == 0 {
+			if style := r.style("table-row", rowNode.attr(nsTable, "style-name")); style != nil {
+				if props := style.child(nsStyle, "table-row-properties"); props != nil {
+					rowHeight, _ = parseLength(props.attr(nsStyle, "row-height"))
+				}
+			}
+		}
+	}
+	if len(table.Rows) == 0 {
+		return Table{}, false
+	}
+
+	// Todas las filas deben tener el mismo número de columnas
+	columns := 0
+	for _, row := range table.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return Table{}, false
+	}
+	for i := range table.Rows {
+		for len(table.Rows[i]) < columns {
+			table.Rows[i] = append(table.Rows[i], TableCell{RowSpan: 1, ColSpan: 1})
+		}
+	}
+
+	// Anchos de columna (table:number-columns-repeated agrupa columnas iguales)
+	for _, column := range node.children(nsTable, "table-column") {
+		width := ""
+		if style := r.style("table-column", column.attr(nsTable, "style-name")); style != nil {
+			if props := style.child(nsStyle, "table-column-properties"); props != nil {
+				if cm, err := parseLength(props.attr(nsStyle, "column-width")); err == nil {
+					width = fmt.Sprintf("%.2fcm", cm)
+				}
+			}
+		}
+		repeated, err := strconv.Atoi(column.attr(nsTable, "number-columns-repeated"))
+		if err != nil || repeated < 1 {
+			repeated = 1
+		}
+		for i := 0; i < repeated; i++ {
+			table.ColumnWidths = append(table.ColumnWidths, width)
+		}
+	}
+	frameWidth, _ := parseLength(width)
+	if len(table.ColumnWidths) != columns {
+		table.ColumnWidths = make([]string, columns)
+	}
+	for i, w := range table.ColumnWidths {
+		if w == "" {
+			table.ColumnWidths[i] = fmt.Sprintf("%.2fcm", frameWidth/float64(columns))
+		}
+	}
+
+	if rowHeight == 0 {
+		frameHeight, _ := parseLength(height)
+		rowHeight = frameHeight / float64(len(table.Rows))
+	}
+	table.RowHeight = fmt.Sprintf("%.2fcm", rowHeight)
+
+	return table, true
+}
+
+// tableCell convierte un table:table-cell en una TableCell con su estilo
+func (r *odpReader) tableCell(node *xmlNode) TableCell {
+	cell := TableCell{RowSpan: 1, ColSpan: 1}
+	if span, err := strconv.Atoi(node.attr(nsTable, "number-rows-spanned")); err == nil && span > 1 {
+		cell.RowSpan = span
+	}
+	if span, err := strconv.Atoi(node.attr(nsTable, "number-columns-spanned")); err == nil && span > 1 {
+		cell.ColSpan = span
+	}
+
+	style := &CellStyle{}
+	var lines []string
+	for i, p := range node.children(nsText, "p") {
+		if i == 0 {
+			style.Text = r.textStyle(p)
+		}
+		var sb strings.Builder
+		for _, run := range r.paragraphRuns(p) {
+			sb.WriteString(run.Text)
+		}
+		lines = append(lines, sb.String())
+	}
+	cell.Text = escapeXML(strings.Join(lines, "\n"))
+
+	if cellStyle := r.style("table-cell", node.attr(nsTable, "style-name")); cellStyle != nil {
+		if graphic := cellStyle.child(nsStyle, "graphic-properties"); graphic != nil {
+			if graphic.attr(nsDraw, "fill") == "solid" {
+				style.FillColor = graphic.attr(nsDraw, "fill-color")
+			}
+			switch value := graphic.attr(nsDraw, "textarea-vertical-align"); value {
+			case "top", "middle", "bottom":
+				style.VerticalAlign = value
+			}
+		}
+		if para := cellStyle.child(nsStyle, "paragraph-properties"); para != nil {
+			// Borde con el formato "0.50pt solid #000000"
+			if fields := strings.Fields(para.attr(nsFo, "border")); len(fields) == 3 {
+				if width, err := parseLength(fields[0]); err == nil {
+					style.BorderWidth = width * 72 / 2.54
+					style.BorderColor = fields[2]
+				}
+			}
+			switch align := para.attr(nsFo, "text-align"); align {
+			case "start":
+				style.HorizontalAlign = "left"
+			case "end":
+				style.HorizontalAlign = "right"
+			case "left", "right", "center", "justify":
+				style.HorizontalAlign = align
+			}
+		}
+	}
+	cell.Style = style
+	return cell
+}
+
+// transition lee la transición definida en el estilo de página de una diapositiva
+func (r *odpReader) transition(styleName string) *Transition {
+	style := r.style("drawing-page", styleName)
+	if style == nil {
+		return nil
+	}
+	props := style.child(nsStyle, "drawing-page-properties")
+	if props == nil || props.attr(nsSmil, "type") == "" {
+		return nil
+	}
+
+	// Buscar el efecto y la dirección que generan los mismos atributos SMIL
+	smilType := props.attr(nsSmil, "type")
+	subtype := props.attr(nsSmil, "subtype")
+	reverse := props.attr(nsSmil, "direction") == "reverse"
+	transition := &Transition{Type: TransitionFade, Direction: FromLeft, Speed: SpeedMedium}
+	types := []TransitionType{TransitionFade, TransitionDissolve, TransitionWipe, TransitionPush,
+		TransitionCover, TransitionUncover, TransitionSplit, TransitionCircle}
+	directions := []TransitionDirection{FromLeft, FromRight, FromTop, FromBottom}
+search:
+	for _, t := range types {
+		for _, direction := range directions {
+			candidate := Transition{Type: t, Direction: direction}
+			cType, cSubtype, cReverse, _ := transitionSMIL(candidate)
+			if cType == smilType && (cSubtype == subtype || subtype == "") && cReverse == reverse {
+				transition.Type, transition.Direction = t, direction
+				break search
+			}
+		}
+	}
+
+	switch speed := props.attr(nsPresentation, "transition-speed"); speed {
+	case SpeedSlow, SpeedMedium, SpeedFast:
+		transition.Speed = speed
+	}
+	if props.attr(nsPresentation, "transition-type") == "automatic" {
+		transition.AutoAdvance = parseDuration(props.attr(nsPresentation, "duration"))
+	}
+
+	if sound := props.child(nsPresentation, "sound"); sound != nil {
+		href := strings.TrimPrefix(sound.attr(nsXlink, "href"), "./")
+		extension := strings.ToLower(path.Ext(href))
+		if data, err := r.readFile(href); err == nil && soundMediaTypes[extension] != "" {
+			transition.Sound = data
+			transition.SoundExtension = extension
+			transition.SoundName = href
+		}
+	}
+	return transition
+}
+
+// elementXMLID devuelve el identificador de un elemento de dibujo (xml:id o draw:id)
+func elementXMLID(node *xmlNode) string {
+	if id := node.attr(nsXML, "id"); id != "" {
+		return id
+	}
+	return node.attr(nsDraw, "id")
+}
+
+// clickAction lee la acción al hacer clic de un elemento de dibujo
+func (r *odpReader) clickAction(node *xmlNode) (ClickAction, bool) {
+	listeners := node.child(nsOffice, "event-listeners")
+	if listeners == nil {
+		return ClickAction{}, false
+	}
+	for _, listener := range listeners.children(nsPresentation, "event-listener") {
+		if listener.attr(nsScript, "event-name") != "dom:click" {
+			continue
+		}
+		switch action := ActionType(listener.attr(nsPresentation, "action")); action {
+		case ActionNextSlide, ActionPreviousSlide, ActionFirstSlide, ActionLastSlide, ActionEndShow:
+			return ClickAction{Type: action}, true
+		case "show":
+			href := listener.attr(nsXlink, "href")
+			if name := strings.TrimPrefix(href, "#"); name != href {
+				index, ok := r.pageNames[name]
+				if !ok {
+					continue
+				}
+				if isGeneratedPageName(name) {
+					return ClickAction{Type: ActionSlide, Slide: index}, true
+				}
+				return ClickAction{Type: ActionSlide, SlideID: name}, true
+			}
+			if validateLink(href) == nil {
+				return ClickAction{Type: ActionURL, URL: href}, true
+			}
+		}
+	}
+	return ClickAction{}, false
+}
+
+// paragraphXMLIDs devuelve los identificadores de los párrafos de un elemento de dibujo
+func paragraphXMLIDs(node *xmlNode) []string {
+	var ids []string
+	for _, child := range node.Children {
+		if id := child.attr(nsXML, "id"); id != "" && child.is(nsText, "p") {
+			ids = append(ids, id)
+		}
+		ids = append(ids, paragraphXMLIDs(child)...)
+	}
+	return ids
+}
+
+// readAnimations lee la secuencia principal de animaciones de una diapositiva. Solo
+// se conservan los efectos que genera goodp sobre elementos que se han leído. Los
+// efectos consecutivos sobre los párrafos de un mismo elemento forman una animación
+// con un clic por párrafo, y los anim:iterate por párrafos, una animación por
+// párrafos con ParagraphInterval.
+func readAnimations(page *xmlNode, ids, paragraphIDs map[string]int, slide *Slide) []Animation {
+	root := page.child(nsAnim, "par")
+	if root == nil {
+		return nil
+	}
+	var sequence *xmlNode
+	for _, seq := range root.children(nsAnim, "seq") {
+		if seq.attr(nsPresentation, "node-type") == "main-sequence" {
+			sequence = seq
+		}
+	}
+	if sequence == nil {
+		return nil
+	}
+
+	var animations []Animation
+	for _, step := range sequence.children(nsAnim, "par") {
+		for _, group := range step.children(nsAnim, "par") {
+			for _, effect := range group.Children {
+				if !effect.is(nsAnim, "par") && !effect.is(nsAnim, "iterate") {
+					continue
+				}
+				animation, target, ok := readAnimation(effect)
+				if !ok {
+					continue
+				}
+				zIndex, ok := ids[target]
+				paragraph := false
+				if paragraphZIndex, isParagraph := paragraphIDs[target]; !ok && isParagraph {
+					zIndex, ok, paragraph = paragraphZIndex, true, true
+					animation.ByParagraph = true
+				}
+				if !ok || !slide.hasElement(zIndex) {
+					continue
+				}
+				animation.Target = zIndex
+				if paragraph && len(animations) > 0 {
+					last := animations[len(animations)-1]
+					if last.ByParagraph && last.Target == zIndex && last.Class == animation.Class && last.Effect == animation.Effect {
+						continue
+					}
+				}
+				animations = append(animations, animation)
+			}
+		}
+	}
+	return animations
+}
+
+// readAnimation convierte el nodo de un efecto en una Animation y devuelve también el
+// identificador del elemento animado
+func readAnimation(node *xmlNode) (Animation, string, bool) {
+	class := AnimationClass(node.attr(nsPresentation, "preset-class"))
+	preset := strings.TrimPrefix(node.attr(nsPresentation, "preset-id"), "ooo-"+string(class)+"-")
+	effects := map[string]AnimationEffect{
+		"appear": EffectAppear, "disappear": EffectAppear, "fade-in": EffectFade, "fade-out": EffectFade,
+		"fly-in": EffectFly, "fly-out": EffectFly, "zoom": EffectZoom, "grow-and-shrink": EffectGrow, "spin": EffectSpin,
+	}
+	animation := Animation{
+		Class:     class,
+		Effect:    effects[preset],
+		Trigger:   AnimationTrigger(node.attr(nsPresentation, "node-type")),
+		Delay:     parseDuration(node.attr(nsSmil, "begin")),
+		Direction: TransitionDirection(strings.TrimPrefix(node.attr(nsPresentation, "preset-sub-type"), "from-")),
+	}
+	if animation.Effect != EffectFly {
+		animation.Direction = ""
+	}
+	if node.is(nsAnim, "iterate") {
+		if node.attr(nsAnim, "iterate-type") != "by-paragraph" {
+			return Animation{}, "", false
+		}
+		animation.ByParagraph = true
+	}
+
+	// La duración es la del paso más largo del efecto, sin contar los cambios de
+	// visibilidad. Los pasos de anim:iterate se aplican al elemento del propio iterate.
+	target := node.attr(nsSmil, "targetElement")
+	for _, child := range node.Children {
+		if target == "" {
+			target = child.attr(nsSmil, "targetElement")
+		}
+		if child.is(nsAnim, "set") {
+			continue
+		}
+		if duration := parseDuration(child.attr(nsSmil, "dur")); duration > animation.Duration {
+			animation.Duration = duration
+		}
+	}
+
+	// Un anim:iterate muestra los párrafos con un solo inicio; sin separación se toma
+	// la duración del efecto para no confundirlo con un clic por párrafo
+	if node.is(nsAnim, "iterate") {
+		animation.ParagraphInterval = parseDuration(node.attr(nsAnim, "iterate-interval"))
+		if animation.ParagraphInterval <= 0 {
+			animation.ParagraphInterval = animation.Duration
+		}
+	}
+
+	prepared, err := prepareAnimation(animation)
+	if err != nil || target == "" {
+		return Animation{}, "", false
+	}
+	return prepared, target, true
+}
+
+// headerFooter lee el pie de página, el número y la fecha que muestra una diapositiva
+func (r *odpReader) headerFooter(page *xmlNode, styleName string) *HeaderFooter {
+	style := r.style("drawing-page", styleName)
+	if style == nil {
+		return nil
+	}
+	props := style.child(nsStyle, "drawing-page-properties")
+	if props == nil {
+		return nil
+	}
+
+	hf := HeaderFooter{
+		SlideNumber:    props.attr(nsPresentation, "display-page-number") == "true",
+		DateTimeFormat: DateShort,
+	}
+	if props.attr(nsPresentation, "display-footer") == "true" {
+		hf.Footer = r.footerDecls[page.attr(nsPresentation, "use-footer-name")]
+	}
+	if decl := r.dateTimeDecls[page.attr(nsPresentation, "use-date-time-name")]; decl != nil && props.attr(nsPresentation, "display-date-time") == "true" {
+		hf.DateTime = true
+		if decl.attr(nsPresentation, "source") == "fixed" {
+			hf.FixedDateTime = decl.text()
+		}
+		for format, name := range dateTimeDataStyles {
+			if decl.attr(nsStyle, "data-style-name") == name {
+				hf.DateTimeFormat = format
+			}
+		}
+	}
+	if hf.Footer == "" && !hf.SlideNumber && !hf.DateTime {
+		return nil
+	}
+	return &hf
+}
+
+// collapseHeaderFooters convierte el pie de página común a todas las diapositivas en
+// el de la presentación
+func collapseHeaderFooters(g *ODPGenerator) {
+	if len(g.Slides) == 0 || g.Slides[0].HeaderFooter == nil {
+		return
+	}
+	common := *g.Slides[0].HeaderFooter
+	for _, slide := range g.Slides {
+		if slide.HeaderFooter == nil || *slide.HeaderFooter != common {
+			return
+		}
+	}
+	g.HeaderFooter = &common
+	for i := range g.Slides {
+		g.Slides[i].HeaderFooter = nil
+	}
+}
+
+// parseDuration convierte una duración ISO 8601 como "PT5S" o "PT00H01M30S" en segundos
+func parseDuration(value string) float64 {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "PT")
+	seconds := 0.0
+	for _, unit := range []struct {
+		suffix string
+		factor float64
+	}{{"H", 3600}, {"M", 60}, {"S", 1}} {
+		i := strings.Index(value, unit.suffix)
+		if i == -1 {
+			continue
+		}
+		number, err := strconv.ParseFloat(value[:i], 64)
+		if err == nil {
+			seconds += number * unit.factor
+		}
+		value = value[i+1:]
+	}
+	return seconds
+}
+
+// notes lee el texto de las notas del orador de una diapositiva
+func (r *odpReader) notes(node *xmlNode) []Paragraph {
+	var paragraphs []Paragraph
+	for _, frame := range node.children(nsDraw, "frame") {
+		box := frame.child(nsDraw, "text-box")
+		if frame.attr(nsPresentation, "class") != "notes" || box == nil {
+			continue
+		}
+		for _, p := range box.children(nsText, "p") {
+			paragraph := Paragraph{Runs: r.paragraphRuns(p)}
+			for i := range paragraph.Runs {
+				paragraph.Runs[i].Text = escapeXML(paragraph.Runs[i].Text)
+			}
+			// Solo se conservan las propiedades de párrafo distintas de las de por defecto
+			if props := r.paragraphProperties(p); *props != *NewDefaultTextProperties() {
+				paragraph.Props = props
+			}
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return paragraphs
+}
+
+// isShapeElement indica si un elemento de dibujo es una de las formas soportadas
+func isShapeElement(local string) bool {
+	switch ShapeType(local) {
+	case ShapeRectangle, ShapeEllipse, ShapeLine, ShapePolygon:
+		return true
+	}
+	return false
+}
+
+// shape convierte un draw:rect, draw:ellipse, draw:line o draw:polygon en una Shape
+func (r *odpReader) shape(node *xmlNode) (Shape, bool) {
+	shape := Shape{Type: ShapeType(node.Name.Local)}
+
+	format := func(value string) string {
+		cm, _ := parseLength(value)
+		return fmt.Sprintf("%.2fcm", cm)
+	}
+	switch shape.Type {
+	case ShapeLine:
+		shape.X1 = format(node.attr(nsSvg, "x1"))
+		shape.Y1 = format(node.attr(nsSvg, "y1"))
+		shape.X2 = format(node.attr(nsSvg, "x2"))
+		shape.Y2 = format(node.attr(nsSvg, "y2"))
+	case ShapePolygon:
+		shape.X, shape.Y, shape.Width, shape.Height = frameGeometry(node)
+		shape.ViewBox = node.attr(nsSvg, "viewBox")
+		shape.Points = node.attr(nsDraw, "points")
+		if shape.ViewBox == "" || shape.Points == "" {
+			return Shape{}, false
+		}
+	default:
+		shape.X, shape.Y, shape.Width, shape.Height = frameGeometry(node)
+		shape.ShapeStyle.CornerRadius, _ = parseLength(node.attr(nsDraw, "corner-radius"))
+	}
+
+	if style := r.style("graphic", node.attr(nsDraw, "style-name")); style != nil {
+		if graphic := style.child(nsStyle, "graphic-properties"); graphic != nil {
+			if graphic.attr(nsDraw, "fill") == "solid" {
+				shape.ShapeStyle.FillColor = graphic.attr(nsDraw, "fill-color")
+			}
+			if stroke := graphic.attr(nsDraw, "stroke"); stroke != "none" {
+				shape.ShapeStyle.StrokeColor = graphic.attr(nsSvg, "stroke-color")
+				if shape.ShapeStyle.StrokeColor == "" {
+					shape.ShapeStyle.StrokeColor = "#000000"
+				}
+				if width, err := parseLength(graphic.attr(nsSvg, "stroke-width")); err == nil {
+					shape.ShapeStyle.StrokeWidth = width * 72 / 2.54
+				}
+				if stroke == "dash" {
+					shape.ShapeStyle.StrokeDash = StrokeDash
+					for _, dash := range []string{StrokeDot, StrokeDashDot} {
+						if graphic.attr(nsDraw, "stroke-dash") == strokeDashName(dash) {
+							shape.ShapeStyle.StrokeDash = dash
+						}
+					}
+				}
+			}
+			shape.ShapeStyle.StartArrow = graphic.attr(nsDraw, "marker-start") != ""
+			shape.ShapeStyle.EndArrow = graphic.attr(nsDraw, "marker-end") != ""
+		}
+	}
+
+	// Texto de la forma (todos los párrafos se unen con saltos de línea)
+	var lines []string
+	for i, p := range node.children(nsText, "p") {
+		if i == 0 {
+			shape.Style = r.textStyle(p)
+			shape.Props = r.paragraphProperties(p)
+			shape.Props.VerticalAlign = r.verticalAlign(node)
+		}
+		var sb strings.Builder
+		for _, run := range r.paragraphRuns(p) {
+			sb.WriteString(run.Text)
+		}
+		lines = append(lines, sb.String())
+	}
+	if len(lines) > 0 {
+		shape.Text = escapeXML(strings.Join(lines, "\n"))
+	}
+
+	return shape, true
+}
+
+// chart convierte un draw:frame con un gráfico incrustado (draw:object) en un Chart.
+// Los archivos del subdocumento se conservan tal cual; los datos de ChartSpec se
+// obtienen de su tabla interna.
+func (r *odpReader) chart(frame *xmlNode) (Chart, bool) {
+	objectName := strings.TrimSuffix(strings.TrimPrefix(frame.child(nsDraw, "object").attr(nsXlink, "href"), "./"), "/")
+	if objectName == "" {
+		return Chart{}, false
+	}
+	data, err := r.readFile(objectName + "/content.xml")
+	if err != nil {
+		return Chart{}, false
+	}
+	doc, err := parseXMLTree(data)
+	if err != nil {
+		return Chart{}, false
+	}
+	chartNode := doc.find(nsChart, "chart")
+	if chartNode == nil {
+		return Chart{}, false
+	}
+
+	x, y, width, height := frameGeometry(frame)
+	chart := Chart{
+		Spec:       r.chartSpec(doc, chartNode),
+		X:          x,
+		Y:          y,
+		Width:      width,
+		Height:     height,
+		ObjectName: objectName,
+	}
+
+	mediaTypes := r.manifestMediaTypes()
+	chart.files = append(chart.files, packageFile{Name: objectName + "/", MediaType: mediaTypeChart})
+	for _, name := range sortedFileNames(r.files) {
+		if !strings.HasPrefix(name, objectName+"/") || strings.HasSuffix(name, "/") {
+			continue
+		}
+		fileData, err := r.readFile(name)
+		if err != nil {
+			return Chart{}, false
+		}
+		mediaType := mediaTypes[name]
+		if mediaType == "" {
+			mediaType = "text/xml"
+		}
+		chart.files = append(chart.files, packageFile{Name: name, MediaType: mediaType, Data: fileData})
+	}
+	return chart, true
+}
+
+// chartSpec obtiene el tipo, el título y los datos de un gráfico incrustado
+func (r *odpReader) chartSpec(doc, chartNode *xmlNode) ChartSpec {
+	spec := ChartSpec{Type: ChartType(strings.TrimPrefix(chartNode.attr(nsChart, "class"), "chart:"))}
+	if spec.Type == "circle" {
+		spec.Type = ChartPie
+	}
+	if title := chartNode.child(nsChart, "title"); title != nil {
+		if p := title.child(nsText, "p"); p != nil {
+			spec.Title = p.text()
+		}
+	}
+	spec.HideLegend = chartNode.child(nsChart, "legend") == nil
+
+	table := doc.find(nsTable, "table")
+	if table == nil {
+		return spec
+	}
+	if header := table.find(nsTable, "table-header-rows"); header != nil {
+		if row := header.child(nsTable, "table-row"); row != nil {
+			for i, cell := range row.children(nsTable, "table-cell") {
+				if i > 0 {
+					spec.Series = append(spec.Series, ChartSeries{Name: cell.text()})
+				}
+			}
+		}
+	}
+	if rows := table.child(nsTable, "table-rows"); rows != nil {
+		for _, row := range rows.children(nsTable, "table-row") {
+			for i, cell := range row.children(nsTable, "table-cell") {
+				if i == 0 {
+					spec.Categories = append(spec.Categories, cell.text())
+					continue
+				}
+				if i-1 >= len(spec.Series) {
+					break
+				}
+				value, _ := strconv.ParseFloat(cell.attr(nsOffice, "value"), 64)
+				spec.Series[i-1].Values = append(spec.Series[i-1].Values, value)
+			}
+		}
+	}
+	return spec
+}
+
+// sortedFileNames devuelve los nombres de los archivos del paquete en orden alfabético
+func sortedFileNames(files map[string]*zip.File) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// image convierte un draw:frame con draw:image en una Image
+func (r *odpReader) image(frame *xmlNode) (Image, bool) {
+	href := strings.TrimPrefix(frame.child(nsDraw, "image").attr(nsXlink, "href"), "./")
+	if href == "" {
+		return Image{}, false
+	}
+	// Las imágenes enlazadas (no incrustadas en el paquete) no se conservan
+	data, err := r.readFile(href)
+	if err != nil || len(data) == 0 {
+		return Image{}, false
+	}
+
+	x, y, width, height := frameGeometry(frame)
+	return Image{
+		Data:   data,
+		X:      x,
+		Y:      y,
+		Width:  width,
+		Height: height,
+		Name:   href,
+	}, true
+}
+
+// sameBackground indica si dos fondos son equivalentes
+func sameBackground(a, b *Background) bool {
+	if a == nil || b == nil || a.Type != b.Type {
+		return false
+	}
+	if a.Type == BackgroundColor {
+		return strings.EqualFold(a.Color, b.Color)
+	}
+	return a.Name == b.Name
+}
+
+// paragraphRuns divide un párrafo en fragmentos de texto (sin escapar) según su
+// estilo, aplicando las reglas de espacios en blanco de OpenDocument (text:s,
+// text:tab y text:line-break)
+func (r *odpReader) paragraphRuns(p *xmlNode) []Run {
+	var runs []Run
+	lastSpace := true // los espacios al inicio del párrafo se ignoran
+
+	link := "" // enlace del texto que se está leyendo
+	write := func(text string, style TextStyle) {
+		if n := len(runs); n > 0 && generateStyleName(runs[n-1].Style) == generateStyleName(style) && runs[n-1].Link == link {
+			runs[n-1].Text += text
+			return
+		}
+		runs = append(runs, Run{Text: text, Style: style, Link: link})
+	}
+
+	var walk func(node *xmlNode, style TextStyle)
+	walk = func(node *xmlNode, style TextStyle) {
+		for _, child := range node.Children {
+			switch {
+			case child.Name.Local == "":
+				var sb strings.Builder
+				for _, c := range child.Text {
+					if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+						if !lastSpace {
+							sb.WriteRune(' ')
+							lastSpace = true
+						}
+						continue
+					}
+					sb.WriteRune(c)
+					lastSpace = false
+				}
+				if sb.Len() > 0 {
+					write(sb.String(), style)
+				}
+			case child.is(nsText, "s"):
+				count, err := strconv.Atoi(child.attr(nsText, "c"))
+				if err != nil || count < 1 {
+					count = 1
+				}
+				write(strings.Repeat(" ", count), style)
+				lastSpace = false
+			case child.is(nsText, "tab"):
+				write("\t", style)
+				lastSpace = false
+			case child.is(nsText, "line-break"):
+				write("\n", style)
+				lastSpace = true
+			case child.is(nsText, "span"):
+				var spanStyle TextStyle
+				r.applyTextProperties(&spanStyle, r.style("text", child.attr(nsText, "style-name")))
+				walk(child, mergeTextStyle(spanStyle, style))
+			case child.is(nsText, "a"):
+				// Los enlaces con esquemas no soportados se quedan como texto normal
+				if href := child.attr(nsXlink, "href"); validateLink(href) == nil {
+					link = href
+				}
+				walk(child, style)
+				link = ""
+			default:
+				walk(child, style)
+			}
+		}
+	}
+
+	var paragraphStyle TextStyle
+	r.applyTextProperties(&paragraphStyle, r.style("paragraph", p.attr(nsText, "style-name")))
+	walk(p, paragraphStyle)
+
+	// Los espacios al final del párrafo también se ignoran
+	if n := len(runs); n > 0 {
+		runs[n-1].Text = strings.TrimRight(runs[n-1].Text, " ")
+		if runs[n-1].Text == "" {
+			runs = runs[:n-1]
+		}
+	}
+	return runs
+}
+
+// mergeTextStyle completa los campos vacíos de style con los de parent
+func mergeTextStyle(style, parent TextStyle) TextStyle {
+	style = inheritTextStyle(style, parent)
+	style.Bold = style.Bold || parent.Bold
+	style.Italic = style.Italic || parent.Italic
+	return style
+}
+
+// parseLength convierte una medida de OpenDocument (por ejemplo "2.5cm" o "1in") a centímetros
+func parseLength(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("medida vacía")
+	}
+
+	units := []struct {
+		suffix string
+		factor float64
+	}{
+		{"cm", 1},
+		{"mm", 0.1},
+		{"in", 2.54},
+		{"pt", 2.54 / 72},
+		{"pc", 2.54 / 6},
+		{"px", 2.54 / 96},
+	}
+	for _, unit := range units {
+		if strings.HasSuffix(value, unit.suffix) {
+			number, err := strconv.ParseFloat(strings.TrimSuffix(value, unit.suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("medida inválida: %s", value)
+			}
+			return number * unit.factor, nil
+		}
+	}
+
+	// Sin unidad se asumen centímetros
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("medida inválida: %s", value)
+	}
+	return number, nil
+}
+
+// xmlNode es un nodo genérico del árbol XML de un documento leído.
+// Los nodos de texto tienen Name vacío y el contenido en Text.
+type xmlNode struct {
+	Name     xml.Name
+	Attr     []xml.Attr
+	Children []*xmlNode
+	Text     string
+}
+
+// parseXMLTree construye el árbol de nodos de un documento XML
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{Name: t.Name, Attr: t.Copy().Attr}
+			parent.Children = append(parent.Children, node)
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.Children = append(parent.Children, &xmlNode{Text: string(t)})
+		}
+	}
+
+	for _, node := range root.Children {
+		if node.Name.Local != "" {
+			return node, nil
+		}
+	}
+	return nil, fmt.Errorf("documento XML vacío")
+}
+
+// is indica si el nodo tiene el espacio de nombres y nombre local indicados
+func (n *xmlNode) is(space, local string) bool {
+	return n.Name.Space == space && n.Name.Local == local
+}
+
+// attr devuelve el valor de un atributo o una cadena vacía si no existe
+func (n *xmlNode) attr(space, local string) string {
+	for _, a := range n.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child devuelve el primer hijo directo con el nombre indicado
+func (n *xmlNode) child(space, local string) *xmlNode {
+	for _, c := range n.Children {
+		if c.is(space, local) {
+			return c
+		}
+	}
+	return nil
+}
+
+// children devuelve todos los hijos directos con el nombre indicado
+func (n *xmlNode) children(space, local string) []*xmlNode {
+	var nodes []*xmlNode
+	for _, c := range n.Children {
+		if c.is(space, local) {
+			nodes = append(nodes, c)
+		}
+	}
+	return nodes
+}
+
+// find busca en profundidad el primer descendiente con el nombre indicado
+func (n *xmlNode) find(space, local string) *xmlNode {
+	for _, c := range n.Children {
+		if c.is(space, local) {
+			return c
+		}
+		if found := c.find(space, local); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// text devuelve el texto de todos los descendientes del nodo, sin formato
+func (n *xmlNode) text() string {
+	var sb strings.Builder
+	for _, c := range n.Children {
+		if c.Name.Local == "" {
+			sb.WriteString(c.Text)
+		} else {
+			sb.WriteString(c.text())
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,629 @@
+package goodp
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// thumbnailSize es el tamaño en píxeles del lado mayor de Thumbnails/thumbnail.png
+const thumbnailSize = 256
+
+// maxRenderSize es el tamaño máximo en píxeles de cada lado de las imágenes que
+// dibuja RenderSlide
+const maxRenderSize = 16384
+
+// Medidas de la fuente de mapa de bits respecto al tamaño del texto
+const (
+	glyphCapHeight = 0.72 // Alto de las mayúsculas (siete filas de la fuente)
+	glyphFill      = 0.8  // Parte del ancho de cada carácter que ocupan sus columnas
+	glyphMinWidth  = 0.5  // Ancho mínimo de cada columna respecto al alto de cada fila
+	glyphMaxWidth  = 1.4  // Ancho máximo de cada columna respecto al alto de cada fila
+	glyphSlant     = 0.2  // Inclinación de la cursiva
+)
+
+// RenderSlide dibuja una diapositiva como imagen con la resolución indicada en
+// puntos por pulgada. Se dibujan el fondo, los elementos de la página maestra y,
+// en orden de ZIndex, los cuadros de texto, las imágenes escaladas a su marco y
+// las formas. El texto usa una fuente de mapa de bits con los anchos de las
+// fuentes estándar, por lo que el resultado sirve como miniatura o vista previa.
+// La imagen no puede pasar de 16384 píxeles por lado.
+func (g *ODPGenerator) RenderSlide(slide *Slide, dpi float64) (image.Image, error) {
+	if slide == nil {
+		return nil, fmt.Errorf("la diapositiva no puede ser nil")
+	}
+	if !(dpi > 0) || math.IsInf(dpi, 0) {
+		return nil, fmt.Errorf("resolución inválida: %g ppp", dpi)
+	}
+	c, err := g.newCanvas(dpi)
+	if err != nil {
+		return nil, err
+	}
+
+	// Las diapositivas sin fondo son blancas
+	c.fillPaths([][]rasterPoint{rectPath(c.bounds())}, color.NRGBA{255, 255, 255, 255})
+	if background := g.slideBackground(*slide); background != nil {
+		c.background(*background)
+	}
+	if master := g.slideMaster(*slide); master != nil {
+		for _, element := range master.SortedElements() {
+			if err := c.element(element); err != nil {
+				return nil, fmt.Errorf("página maestra %q: %v", master.Name, err)
+			}
+		}
+	}
+	for _, element := range slide.SortedElements() {
+		if err := c.element(element); err != nil {
+			return nil, err
+		}
+	}
+	return c.img, nil
+}
+
+// newCanvas crea una imagen del tamaño de las diapositivas con la resolución
+// indicada, que no puede pasar de maxRenderSize píxeles por lado
+func (g *ODPGenerator) newCanvas(dpi float64) (*canvas, error) {
+	size := g.SlideSize
+	if !(size.Width > 0) || !(size.Height > 0) || math.IsInf(size.Width, 0) || math.IsInf(size.Height, 0) {
+		return nil, fmt.Errorf("tamaño de diapositiva inválido: %gx%g cm", size.Width, size.Height)
+	}
+	scale := dpi / 72
+	width := math.Max(1, math.Round(size.Width*pointsPerCm*scale))
+	height := math.Max(1, math.Round(size.Height*pointsPerCm*scale))
+	if width > maxRenderSize || height > maxRenderSize {
+		return nil, fmt.Errorf("la imagen de %.0fx%.0f píxeles supera el máximo de %d píxeles por lado",
+			width, height, maxRenderSize)
+	}
+	return &canvas{img: image.NewRGBA(image.Rect(0, 0, int(width), int(height))), scale: scale}, nil
+}
+
+// thumbnail genera la miniatura PNG de la primera diapositiva que muestran los
+// gestores de archivos. Si la diapositiva no se puede dibujar (también si el tamaño
+// de las diapositivas no es válido), la miniatura es una imagen en blanco de
+// thumbnailSize píxeles de lado en lugar de impedir el guardado.
+func (g *ODPGenerator) thumbnail() ([]byte, error) {
+	dpi := thumbnailSize * 2.54 / math.Max(g.SlideSize.Width, g.SlideSize.Height)
+	img, err := g.RenderSlide(&g.Slides[0], dpi)
+	if err != nil {
+		blank := &canvas{img: image.NewRGBA(image.Rect(0, 0, thumbnailSize, thumbnailSize)), scale: 1}
+		blank.fillPaths([][]rasterPoint{rectPath(blank.bounds())}, color.NRGBA{255, 255, 255, 255})
+		img = blank.img
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rasterPoint es un punto de la imagen en puntos tipográficos
+type rasterPoint struct {
+	X, Y float64
+}
+
+// canvas es la imagen en la que se dibuja una diapositiva
+type canvas struct {
+	img    *image.RGBA
+	scale  float64                                // Píxeles por punto
+	decode func(data []byte) (image.Image, error) // Decodificación de las imágenes
+}
+
+// bounds devuelve el rectángulo de toda la imagen en puntos
+func (c *canvas) bounds() pointRect {
+	size := c.img.Bounds().Size()
+	return pointRect{Width: float64(size.X) / c.scale, Height: float64(size.Y) / c.scale}
+}
+
+// background dibuja el fondo de la diapositiva
+func (c *canvas) background(background Background) {
+	if background.Type == BackgroundImage {
+		if img, err := decodeImage(background.Data); err == nil {
+			c.drawImage(img, c.bounds())
+		}
+		return
+	}
+	if fill, ok := renderColor(background.Color); ok {
+		c.fillPaths([][]rasterPoint{rectPath(c.bounds())}, fill)
+	}
+}
+
+// element dibuja un cuadro de texto, una imagen o una forma. Los demás elementos
+// no se dibujan.
+func (c *canvas) element(element DrawableElement) error {
+	switch data := element.Data.(type) {
+	case TextBox:
+		frame, err := pointsFrame(data.X, data.Y, data.Width, data.Height)
+		if err != nil {
+			return err
+		}
+		c.textBox(data, frame)
+	case Image:
+		frame, err := pointsFrame(data.X, data.Y, data.Width, data.Height)
+		if err != nil {
+			return err
+		}
+		// Las imágenes que no se pueden decodificar (SVG...) se omiten
+		if img, err := decodeImage(data.Data); err == nil {
+			c.drawImage(img, frame)
+		}
+	case Shape:
+		return c.shape(data)
+	}
+	return nil
+}
+
+// drawImage dibuja una imagen escalada al marco indicado, promediando varias
+// muestras por píxel al reducirla
+func (c *canvas) drawImage(src image.Image, frame pointRect) {
+	x0, y0 := int(math.Round(frame.X*c.scale)), int(math.Round(frame.Y*c.scale))
+	x1, y1 := int(math.Round((frame.X+frame.Width)*c.scale)), int(math.Round((frame.Y+frame.Height)*c.scale))
+	if x1 <= x0 || y1 <= y0 {
+		return
+	}
+	bounds := src.Bounds()
+	stepX := float64(bounds.Dx()) / float64(x1-x0)
+	stepY := float64(bounds.Dy()) / float64(y1-y0)
+	samplesX := int(math.Min(4, math.Max(1, math.Ceil(stepX))))
+	samplesY := int(math.Min(4, math.Max(1, math.Ceil(stepY))))
+	samples := uint32(samplesX * samplesY)
+
+	clip := c.img.Bounds().Intersect(image.Rect(x0, y0, x1, y1))
+	for y := clip.Min.Y; y < clip.Max.Y; y++ {
+		for x := clip.Min.X; x < clip.Max.X; x++ {
+			var r, g, b, a uint32
+			for j := 0; j < samplesY; j++ {
+				v := bounds.Min.Y + int((float64(y-y0)+(float64(j)+0.5)/float64(samplesY))*stepY)
+				for i := 0; i < samplesX; i++ {
+					u := bounds.Min.X + int((float64(x-x0)+(float64(i)+0.5)/float64(samplesX))*stepX)
+					sr, sg, sb, sa := src.At(min(u, bounds.Max.X-1), min(v, bounds.Max.Y-1)).RGBA()
+					r, g, b, a = r+sr, g+sg, b+sb, a+sa
+				}
+			}
+			// Composición "over" con colores premultiplicados de 16 bits
+			pix := c.img.Pix[c.img.PixOffset(x, y):]
+			rest := 1 - float64(a/samples)/0xffff
+			pix[0] = uint8(float64(r/samples)/257 + float64(pix[0])*rest + 0.5)
+			pix[1] = uint8(float64(g/samples)/257 + float64(pix[1])*rest + 0.5)
+			pix[2] = uint8(float64(b/samples)/257 + float64(pix[2])*rest + 0.5)
+			pix[3] = 0xff
+		}
+	}
+}
+
+// textBox dibuja las líneas de un cuadro de texto
+func (c *canvas) textBox(tb TextBox, frame pointRect) {
+	for _, line := range layoutTextBox(tb, frame.Width, frame.Height) {
+		for _, fragment := range line.Fragments {
+			fill, ok := renderColor(fragment.Style.Color)
+			if !ok {
+				fill = color.NRGBA{0, 0, 0, 255}
+			}
+			c.text(fragment, frame.X+fragment.X, frame.Y+line.Baseline, line.WordSpacing, fill)
+		}
+	}
+}
+
+// text dibuja un fragmento de texto con la fuente de mapa de bits. Cada carácter
+// avanza el ancho que tendría con la fuente estándar, para que las líneas ocupen
+// lo mismo que al calcular su colocación.
+func (c *canvas) text(fragment textFragment, x, baseline, wordSpacing float64, fill color.NRGBA) {
+	font := fontFor(fragment.Style)
+	size := fontSizePoints(fragment.Style)
+	cellHeight := size * glyphCapHeight / 7
+	bold := 0.0
+	if fragment.Style.Bold {
+		bold = cellHeight / 2
+	}
+	slant := 0.0
+	if fragment.Style.Italic {
+		slant = glyphSlant
+	}
+
+	var paths [][]rasterPoint
+	for _, char := range fragment.Text {
+		advance := font.width(string(char), size)
+		if char == ' ' {
+			advance += wordSpacing
+		}
+		if glyph, ok := glyphFor(char); ok {
+			// El carácter se centra en su ancho según las columnas que usa
+			var used uint8
+			for _, row := range glyph {
+				used |= row
+			}
+			first, last := 0, 4
+			for first < 4 && used&(0x10>>first) == 0 {
+				first++
+			}
+			for last > first && used&(0x10>>last) == 0 {
+				last--
+			}
+			// Las columnas se ensanchan o estrechan para ocupar el ancho del carácter
+			columns := float64(last - first + 1)
+			cellWidth := math.Max(glyphMinWidth*cellHeight, math.Min(glyphMaxWidth*cellHeight, (advance*glyphFill-bold)/columns))
+			left := x + (advance-columns*cellWidth-bold)/2 - float64(first)*cellWidth
+
+			for i, row := range glyph {
+				top := baseline + float64(i-7)*cellHeight
+				for col := 0; col < 5; col++ {
+					if row&(0x10>>col) == 0 {
+						continue
+					}
+					// Los píxeles seguidos de una fila forman un único rectángulo
+					end := col
+					for end < 4 && row&(0x10>>(end+1)) != 0 {
+						end++
+					}
+					shear := func(y float64) float64 { return (baseline - y) * slant }
+					x0, x1 := left+float64(col)*cellWidth, left+float64(end+1)*cellWidth+bold
+					bottom := top + cellHeight
+					paths = append(paths, []rasterPoint{
+						{x0 + shear(top), top}, {x1 + shear(top), top},
+						{x1 + shear(bottom), bottom}, {x0 + shear(bottom), bottom},
+					})
+					col = end
+				}
+			}
+		}
+		x += advance
+	}
+	c.fillPaths(paths, fill)
+}
+
+// shape dibuja el relleno, el trazo y el texto de una forma
+func (c *canvas) shape(shape Shape) error {
+	style := shape.ShapeStyle
+	stroke, hasStroke := renderColor(style.StrokeColor)
+
+	if shape.Type == ShapeLine {
+		var coords [4]float64
+		for i, value := range []string{shape.X1, shape.Y1, shape.X2, shape.Y2} {
+			cm, err := parseLength(value)
+			if err != nil {
+				return err
+			}
+			coords[i] = cm * pointsPerCm
+		}
+		if hasStroke {
+			c.line(rasterPoint{coords[0], coords[1]}, rasterPoint{coords[2], coords[3]}, style, stroke)
+		}
+		return nil
+	}
+
+	frame, err := pointsFrame(shape.X, shape.Y, shape.Width, shape.Height)
+	if err != nil {
+		return err
+	}
+	var outline []rasterPoint
+	switch shape.Type {
+	case ShapeRectangle:
+		outline = roundedRectPath(frame, style.CornerRadius*pointsPerCm)
+	case ShapeEllipse:
+		outline = ellipsePath(frame)
+	case ShapePolygon:
+		if outline, err = polygonPath(shape, frame); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	if fill, ok := renderColor(style.FillColor); ok {
+		c.fillPaths([][]rasterPoint{outline}, fill)
+	}
+	if hasStroke {
+		c.stroke(append(outline, outline[0]), style, stroke)
+	}
+	if shape.Text != "" {
+		c.textBox(TextBox{Content: shape.Text, Style: shape.Style, Props: shape.Props}, frame)
+	}
+	return nil
+}
+
+// line dibuja una línea con sus puntas de flecha, que ocupan el final de la línea
+func (c *canvas) line(start, end rasterPoint, style ShapeStyle, stroke color.NRGBA) {
+	length := math.Hypot(end.X-start.X, end.Y-start.Y)
+	if length == 0 {
+		return
+	}
+	dx, dy := (end.X-start.X)/length, (end.Y-start.Y)/length
+
+	// Mismo tamaño que la punta "goodpArrow" de styles.xml (viewBox de 20x30)
+	arrowWidth := 0.25*pointsPerCm + 3*style.StrokeWidth
+	arrowLength := arrowWidth * 1.5
+	var arrows [][]rasterPoint
+	arrow := func(tip rasterPoint, dx, dy float64) rasterPoint {
+		base := rasterPoint{tip.X - dx*arrowLength, tip.Y - dy*arrowLength}
+		nx, ny := -dy*arrowWidth/2, dx*arrowWidth/2
+		arrows = append(arrows, []rasterPoint{tip, {base.X + nx, base.Y + ny}, {base.X - nx, base.Y - ny}})
+		// La línea llega hasta dentro de la punta para que no quede un hueco
+		return rasterPoint{base.X + dx*arrowLength/3, base.Y + dy*arrowLength/3}
+	}
+	if style.StartArrow {
+		start = arrow(start, -dx, -dy)
+	}
+	if style.EndArrow {
+		end = arrow(end, dx, dy)
+	}
+
+	c.stroke([]rasterPoint{start, end}, style, stroke)
+	c.fillPaths(arrows, stroke)
+}
+
+// stroke dibuja el trazo de un camino con el grosor y el tipo de trazo del estilo
+func (c *canvas) stroke(path []rasterPoint, style ShapeStyle, stroke color.NRGBA) {
+	// Un grosor de 0 es el trazo más fino posible, como en Impress
+	width := math.Max(style.StrokeWidth, 1/c.scale)
+	var paths [][]rasterPoint
+	for _, piece := range dashPieces(path, dashPattern(style.StrokeDash, width)) {
+		for i := 1; i < len(piece); i++ {
+			if quad := segmentPath(piece[i-1], piece[i], width); quad != nil {
+				paths = append(paths, quad)
+			}
+		}
+		// Las uniones entre segmentos se redondean
+		for i := 1; i < len(piece)-1; i++ {
+			paths = append(paths, discPath(piece[i], width/2))
+		}
+	}
+	c.fillPaths(paths, stroke)
+}
+
+// dashPattern devuelve las longitudes en puntos de los trazos y huecos de un tipo
+// de trazo, como en las definiciones draw:stroke-dash de styles.xml, o nil para
+// el trazo continuo
+func dashPattern(dash string, width float64) []float64 {
+	dashLength, dotLength, distance := 0.2*pointsPerCm, math.Max(0.02*pointsPerCm, width), 0.1*pointsPerCm
+	switch dash {
+	case StrokeDash:
+		return []float64{dashLength, distance}
+	case StrokeDot:
+		return []float64{dotLength, 0.08 * pointsPerCm}
+	case StrokeDashDot:
+		return []float64{dashLength, distance, dotLength, distance}
+	}
+	return nil
+}
+
+// dashPieces divide un camino en los trozos visibles de un patrón de trazos
+func dashPieces(path []rasterPoint, pattern []float64) [][]rasterPoint {
+	if len(pattern) == 0 {
+		return [][]rasterPoint{path}
+	}
+	var pieces [][]rasterPoint
+	index, left, on := 0, pattern[0], true
+	current := []rasterPoint{path[0]}
+	for i := 1; i < len(path); i++ {
+		from, to := path[i-1], path[i]
+		length := math.Hypot(to.X-from.X, to.Y-from.Y)
+		pos := 0.0
+		for length-pos > left {
+			pos += left
+			point := rasterPoint{from.X + (to.X-from.X)*pos/length, from.Y + (to.Y-from.Y)*pos/length}
+			if on {
+				pieces = append(pieces, append(current, point))
+			}
+			current = []rasterPoint{point}
+			index = (index + 1) % len(pattern)
+			left, on = pattern[index], !on
+		}
+		left -= length - pos
+		current = append(current, to)
+	}
+	if on && len(current) > 1 {
+		pieces = append(pieces, current)
+	}
+	return pieces
+}
+
+// segmentPath devuelve el rectángulo que ocupa un segmento de trazo del grosor indicado
+func segmentPath(from, to rasterPoint, width float64) []rasterPoint {
+	length := math.Hypot(to.X-from.X, to.Y-from.Y)
+	if length == 0 {
+		return nil
+	}
+	nx, ny := -(to.Y-from.Y)/length*width/2, (to.X-from.X)/length*width/2
+	return []rasterPoint{
+		{from.X + nx, from.Y + ny}, {to.X + nx, to.Y + ny},
+		{to.X - nx, to.Y - ny}, {from.X - nx, from.Y - ny},
+	}
+}
+
+// discPath devuelve un círculo, recorrido en el mismo sentido que segmentPath para
+// que las zonas en las que se solapan sigan rellenas
+func discPath(center rasterPoint, radius float64) []rasterPoint {
+	const steps = 16
+	path := make([]rasterPoint, steps)
+	for i := range path {
+		angle := -2 * math.Pi * float64(i) / steps
+		path[i] = rasterPoint{center.X + radius*math.Cos(angle), center.Y + radius*math.Sin(angle)}
+	}
+	return path
+}
+
+// rectPath devuelve las esquinas de un rectángulo
+func rectPath(rect pointRect) []rasterPoint {
+	return []rasterPoint{
+		{rect.X, rect.Y}, {rect.X + rect.Width, rect.Y},
+		{rect.X + rect.Width, rect.Y + rect.Height}, {rect.X, rect.Y + rect.Height},
+	}
+}
+
+// roundedRectPath devuelve el contorno de un rectángulo con las esquinas redondeadas
+func roundedRectPath(rect pointRect, radius float64) []rasterPoint {
+	radius = math.Min(radius, math.Min(rect.Width, rect.Height)/2)
+	if radius <= 0 {
+		return rectPath(rect)
+	}
+	const steps = 8
+	corners := []struct{ X, Y, Angle float64 }{
+		{rect.X + rect.Width - radius, rect.Y + radius, -math.Pi / 2},
+		{rect.X + rect.Width - radius, rect.Y + rect.Height - radius, 0},
+		{rect.X + radius, rect.Y + rect.Height - radius, math.Pi / 2},
+		{rect.X + radius, rect.Y + radius, math.Pi},
+	}
+	var path []rasterPoint
+	for _, corner := range corners {
+		for i := 0; i <= steps; i++ {
+			angle := corner.Angle + math.Pi/2*float64(i)/steps
+			path = append(path, rasterPoint{corner.X + radius*math.Cos(angle), corner.Y + radius*math.Sin(angle)})
+		}
+	}
+	return path
+}
+
+// ellipsePath devuelve el contorno de la elipse inscrita en un rectángulo
+func ellipsePath(rect pointRect) []rasterPoint {
+	const steps = 72
+	path := make([]rasterPoint, steps)
+	for i := range path {
+		angle := 2 * math.Pi * float64(i) / steps
+		path[i] = rasterPoint{
+			rect.X + rect.Width/2*(1+math.Cos(angle)),
+			rect.Y + rect.Height/2*(1+math.Sin(angle)),
+		}
+	}
+	return path
+}
+
+// polygonPath convierte los puntos de un polígono, relativos a su ViewBox, en
+// puntos de la diapositiva
+func polygonPath(shape Shape, frame pointRect) ([]rasterPoint, error) {
+	box := strings.Fields(shape.ViewBox)
+	if len(box) != 4 {
+		return nil, fmt.Errorf("viewBox inválido en el polígono: %q", shape.ViewBox)
+	}
+	var viewBox [4]float64
+	for i, value := range box {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("viewBox inválido en el polígono: %q", shape.ViewBox)
+		}
+		viewBox[i] = number
+	}
+	if viewBox[2] <= 0 || viewBox[3] <= 0 {
+		return nil, fmt.Errorf("viewBox inválido en el polígono: %q", shape.ViewBox)
+	}
+
+	var path []rasterPoint
+	for _, pair := range strings.Fields(shape.Points) {
+		coords := strings.Split(pair, ",")
+		if len(coords) != 2 {
+			return nil, fmt.Errorf("punto inválido en el polígono: %q", pair)
+		}
+		x, errX := strconv.ParseFloat(coords[0], 64)
+		y, errY := strconv.ParseFloat(coords[1], 64)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("punto inválido en el polígono: %q", pair)
+		}
+		path = append(path, rasterPoint{
+			frame.X + (x-viewBox[0])/viewBox[2]*frame.Width,
+			frame.Y + (y-viewBox[1])/viewBox[3]*frame.Height,
+		})
+	}
+	if len(path) < 3 {
+		return nil, fmt.Errorf("un polígono necesita al menos tres puntos")
+	}
+	return path, nil
+}
+
+// fillPaths rellena un conjunto de caminos cerrados con la regla del número de
+// vueltas distinto de cero, con suavizado de bordes: cada fila de píxeles se
+// muestrea en varias líneas horizontales y se acumula la parte cubierta de cada
+// píxel.
+func (c *canvas) fillPaths(paths [][]rasterPoint, fill color.NRGBA) {
+	const samples = 4
+	type crossing struct {
+		X   float64
+		Dir int
+	}
+	bounds := c.img.Bounds()
+
+	top, bottom := math.Inf(1), math.Inf(-1)
+	for _, path := range paths {
+		for _, p := range path {
+			top, bottom = math.Min(top, p.Y*c.scale), math.Max(bottom, p.Y*c.scale)
+		}
+	}
+	if math.IsInf(top, 0) {
+		return
+	}
+	firstRow := max(bounds.Min.Y, int(math.Floor(top)))
+	lastRow := min(bounds.Max.Y, int(math.Ceil(bottom)))
+
+	coverage := make([]float64, bounds.Dx())
+	var crossings []crossing
+	for row := firstRow; row < lastRow; row++ {
+		clear(coverage)
+		left, right := bounds.Max.X, bounds.Min.X
+		for sample := 0; sample < samples; sample++ {
+			y := float64(row) + (float64(sample)+0.5)/samples
+			crossings = crossings[:0]
+			for _, path := range paths {
+				for i := range path {
+					a, b := path[i], path[(i+1)%len(path)]
+					ay, by := a.Y*c.scale, b.Y*c.scale
+					if (ay <= y) == (by <= y) {
+						continue
+					}
+					dir := 1
+					if by < ay {
+						dir = -1
+					}
+					crossings = append(crossings, crossing{(a.X + (b.X-a.X)*(y-ay)/(by-ay)) * c.scale, dir})
+				}
+			}
+			sort.Slice(crossings, func(i, j int) bool { return crossings[i].X < crossings[j].X })
+
+			winding, start := 0, 0.0
+			for _, cross := range crossings {
+				previous := winding
+				winding += cross.Dir
+				if previous == 0 && winding != 0 {
+					start = cross.X
+					continue
+				}
+				if previous == 0 || winding != 0 {
+					continue
+				}
+				// Tramo cubierto entre start y cross.X
+				from := math.Max(start, float64(bounds.Min.X))
+				to := math.Min(cross.X, float64(bounds.Max.X))
+				for x := int(math.Floor(from)); float64(x) < to; x++ {
+					covered := math.Min(to, float64(x+1)) - math.Max(from, float64(x))
+					coverage[x-bounds.Min.X] += covered / samples
+					left, right = min(left, x), max(right, x)
+				}
+			}
+		}
+
+		for x := left; x <= right; x++ {
+			alpha := math.Min(1, coverage[x-bounds.Min.X]) * float64(fill.A) / 255
+			if alpha <= 0 {
+				continue
+			}
+			pix := c.img.Pix[c.img.PixOffset(x, row):]
+			pix[0] = uint8(float64(pix[0])*(1-alpha) + float64(fill.R)*alpha + 0.5)
+			pix[1] = uint8(float64(pix[1])*(1-alpha) + float64(fill.G)*alpha + 0.5)
+			pix[2] = uint8(float64(pix[2])*(1-alpha) + float64(fill.B)*alpha + 0.5)
+			pix[3] = 0xff
+		}
+	}
+}
+
+// renderColor convierte un color "#RRGGBB" en un color opaco
+func renderColor(value string) (color.NRGBA, bool) {
+	hex := pptxColor(value)
+	if hex == "" {
+		return color.NRGBA{}, false
+	}
+	rgb, _ := strconv.ParseUint(hex, 16, 32)
+	return color.NRGBA{uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb), 0xff}, true
+}
@@ -0,0 +1,91 @@
+package goodp
+
+import "fmt"
+
+// Run es un fragmento de texto con su propio estilo dentro de un párrafo
+type Run struct {
+	Text  string    `json:"text" yaml:"text"`
+	Style TextStyle `json:"style" yaml:"style"`
+	Link  string    `json:"link,omitempty" yaml:"link,omitempty"` // Dirección que se abre al hacer clic en el texto (http, https, mailto...)
+}
+
+// Paragraph es un párrafo de texto enriquecido formado por varios fragmentos.
+// Si Props es nil, el párrafo usa la alineación y sangrías del cuadro de texto.
+type Paragraph struct {
+	Runs  []Run           `json:"runs" yaml:"runs"`
+	Props *TextProperties `json:"props,omitempty" yaml:"props,omitempty"`
+}
+
+// NewTextStyle crea un estilo de texto con los mismos parámetros que SetTextStyle
+func NewTextStyle(fontSize float64, fontFamily, color string, bold, italic bool) TextStyle {
+	return TextStyle{
+		FontSize:   fmt.Sprintf("%.2fpt", fontSize),
+		FontFamily: fontFamily,
+		Color:      color,
+		Bold:       bold,
+		Italic:     italic,
+	}
+}
+
+// AddRichTextBox añade un cuadro de texto con varios párrafos, cada uno formado por
+// fragmentos con estilos distintos, y devuelve un puntero a él. El tamaño, la fuente y el color que no se indiquen
+// en un fragmento se toman del estilo actual de la diapositiva (SetTextStyle).
+func (g *ODPGenerator) AddRichTextBox(slide *Slide, paragraphs []Paragraph, x, y, width, height float64, props *TextProperties, zIndex ...int) (*TextBox, error) {
+	if g.slideIndex(slide) == -1 {
+		return nil, fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	prepared, err := prepareParagraphs(paragraphs, slide.currentStyle)
+	if err != nil {
+		return nil, err
+	}
+	textBox := TextBox{Paragraphs: prepared}
+	return slide.addTextBox(textBox, x, y, width, height, props, zIndex...), nil
+}
+
+// prepareParagraphs copia los párrafos escapando el texto de los fragmentos y
+// completando sus estilos con el estilo base. Los enlaces de los fragmentos tienen que
+// ser direcciones absolutas con un esquema soportado.
+func prepareParagraphs(paragraphs []Paragraph, base TextStyle) ([]Paragraph, error) {
+	prepared := make([]Paragraph, 0, len(paragraphs))
+	for _, p := range paragraphs {
+		runs := make([]Run, 0, len(p.Runs))
+		for _, run := range p.Runs {
+			if run.Link != "" {
+				if err := validateLink(run.Link); err != nil {
+					return nil, err
+				}
+			}
+			runs = append(runs, Run{
+				Text:  escapeXML(run.Text),
+				Style: inheritTextStyle(run.Style, base),
+				Link:  run.Link,
+			})
+		}
+		prepared = append(prepared, Paragraph{Runs: runs, Props: p.Props})
+	}
+	return prepared, nil
+}
+
+// inheritTextStyle completa el tamaño, la fuente y el color vacíos de style con los de base
+func inheritTextStyle(style, base TextStyle) TextStyle {
+	if style.FontSize == "" {
+		style.FontSize = base.FontSize
+	}
+	if style.FontFamily == "" {
+		style.FontFamily = base.FontFamily
+	}
+	if style.Color == "" {
+		style.Color = base.Color
+	}
+	return style
+}
+
+// richParagraphStyleID devuelve el estilo del párrafo index de un cuadro de texto
+// enriquecido. Los párrafos sin propiedades propias usan el estilo del cuadro.
+func richParagraphStyleID(scope string, box TextBox, index int) string {
+	props := box.Paragraphs[index].Props
+	if props == nil {
+		return generateParaStyleID(scope, box.ZIndex, box.Props)
+	}
+	return generateParaStyleID(fmt.Sprintf("%s_%d_p", scope, box.ZIndex), index, props)
+}
@@ -0,0 +1,224 @@
+package goodp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ShapeType indica el tipo de una forma vectorial
+type ShapeType string
+
+const (
+	ShapeRectangle ShapeType = "rect"
+	ShapeEllipse   ShapeType = "ellipse"
+	ShapeLine      ShapeType = "line"
+	ShapePolygon   ShapeType = "polygon"
+)
+
+// Tipos de trazo de las formas
+const (
+	StrokeSolid   = ""
+	StrokeDash    = "dash"
+	StrokeDot     = "dot"
+	StrokeDashDot = "dash-dot"
+)
+
+// ShapeStyle define el relleno y el borde de una forma
+type ShapeStyle struct {
+	FillColor    string  // Color de relleno (#RRGGBB), vacío para sin relleno
+	StrokeColor  string  // Color del trazo (#RRGGBB), vacío para sin trazo
+	StrokeWidth  float64 // Grosor del trazo en puntos
+	StrokeDash   string  // StrokeSolid, StrokeDash, StrokeDot o StrokeDashDot
+	CornerRadius float64 // Radio de las esquinas en cm (solo rectángulos)
+	StartArrow   bool    // Punta de flecha al inicio (solo líneas)
+	EndArrow     bool    // Punta de flecha al final (solo líneas)
+}
+
+// Point es un punto de la diapositiva, en cm
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Shape es una forma vectorial: rectángulo, elipse, línea o polígono.
+// Los rectángulos, elipses y polígonos pueden contener texto.
+type Shape struct {
+	Type       ShapeType
+	X          string // Posición X en cm
+	Y          string // Posición Y en cm
+	Width      string // Ancho en cm
+	Height     string // Alto en cm
+	X1         string // Inicio de la línea en cm
+	Y1         string
+	X2         string // Fin de la línea en cm
+	Y2         string
+	ViewBox    string // Sistema de coordenadas de los puntos del polígono
+	Points     string // Puntos del polígono, relativos a ViewBox
+	ShapeStyle ShapeStyle
+	Text       string
+	Style      TextStyle
+	Props      *TextProperties
+	ZIndex     int
+}
+
+// Estilos por defecto de las formas
+var (
+	defaultShapeStyle = ShapeStyle{
+		FillColor:   "#4472C4",
+		StrokeColor: "#2F528F",
+		StrokeWidth: 1,
+	}
+	defaultLineStyle = ShapeStyle{
+		StrokeColor: "#000000",
+		StrokeWidth: 1,
+	}
+)
+
+// polygonUnits es el número de unidades del ViewBox de un polígono por cm
+const polygonUnits = 1000
+
+// AddRectangle añade un rectángulo a la diapositiva y devuelve un puntero a él. Si
+// style es nil se usa el estilo por defecto.
+func (g *ODPGenerator) AddRectangle(slide *Slide, x, y, width, height float64, style *ShapeStyle, zIndex ...int) (*Shape, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("las dimensiones de la forma deben ser positivas")
+	}
+	return g.addShape(slide, Shape{
+		Type:   ShapeRectangle,
+		X:      fmt.Sprintf("%.2fcm", x),
+		Y:      fmt.Sprintf("%.2fcm", y),
+		Width:  fmt.Sprintf("%.2fcm", width),
+		Height: fmt.Sprintf("%.2fcm", height),
+	}, style, defaultShapeStyle, zIndex...)
+}
+
+// AddEllipse añade una elipse inscrita en el rectángulo indicado
+func (g *ODPGenerator) AddEllipse(slide *Slide, x, y, width, height float64, style *ShapeStyle, zIndex ...int) (*Shape, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("las dimensiones de la forma deben ser positivas")
+	}
+	return g.addShape(slide, Shape{
+		Type:   ShapeEllipse,
+		X:      fmt.Sprintf("%.2fcm", x),
+		Y:      fmt.Sprintf("%.2fcm", y),
+		Width:  fmt.Sprintf("%.2fcm", width),
+		Height: fmt.Sprintf("%.2fcm", height),
+	}, style, defaultShapeStyle, zIndex...)
+}
+
+// AddLine añade una línea entre (x1, y1) y (x2, y2). Con StartArrow y EndArrow
+// se convierte en una flecha.
+func (g *ODPGenerator) AddLine(slide *Slide, x1, y1, x2, y2 float64, style *ShapeStyle, zIndex ...int) (*Shape, error) {
+	return g.addShape(slide, Shape{
+		Type: ShapeLine,
+		X1:   fmt.Sprintf("%.2fcm", x1),
+		Y1:   fmt.Sprintf("%.2fcm", y1),
+		X2:   fmt.Sprintf("%.2fcm", x2),
+		Y2:   fmt.Sprintf("%.2fcm", y2),
+	}, style, defaultLineStyle, zIndex...)
+}
+
+// AddPolygon añade un polígono cerrado con los vértices indicados (al menos tres)
+func (g *ODPGenerator) AddPolygon(slide *Slide, points []Point, style *ShapeStyle, zIndex ...int) (*Shape, error) {
+	if len(points) < 3 {
+		return nil, fmt.Errorf("un polígono necesita al menos tres puntos")
+	}
+
+	minX, minY := points[0].X, points[0].Y
+	maxX, maxY := minX, minY
+	for _, p := range points[1:] {
+		minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+	}
+	if maxX-minX <= 0 || maxY-minY <= 0 {
+		return nil, fmt.Errorf("los puntos del polígono no pueden estar alineados")
+	}
+
+	// Los puntos se expresan relativos a la esquina del polígono
+	coords := make([]string, 0, len(points))
+	for _, p := range points {
+		coords = append(coords, fmt.Sprintf("%d,%d",
+			int(math.Round((p.X-minX)*polygonUnits)), int(math.Round((p.Y-minY)*polygonUnits))))
+	}
+
+	return g.addShape(slide, Shape{
+		Type:    ShapePolygon,
+		X:       fmt.Sprintf("%.2fcm", minX),
+		Y:       fmt.Sprintf("%.2fcm", minY),
+		Width:   fmt.Sprintf("%.2fcm", maxX-minX),
+		Height:  fmt.Sprintf("%.2fcm", maxY-minY),
+		ViewBox: fmt.Sprintf("0 0 %d %d", int(math.Round((maxX-minX)*polygonUnits)), int(math.Round((maxY-minY)*polygonUnits))),
+		Points:  strings.Join(coords, " "),
+	}, style, defaultShapeStyle, zIndex...)
+}
+
+// SetText establece el texto que se muestra dentro de la forma, con el estilo de
+// texto que tenía la diapositiva al crearla. Si props es nil se centra el texto.
+func (s *Shape) SetText(content string, props *TextProperties) {
+	if props == nil {
+		props = &TextProperties{HorizontalAlign: "center", VerticalAlign: "middle"}
+	}
+	s.Text = escapeXML(content)
+	s.Props = props
+}
+
+// addShape añade una forma a la diapositiva y devuelve un puntero a ella
+func (g *ODPGenerator) addShape(slide *Slide, shape Shape, style *ShapeStyle, defaultStyle ShapeStyle, zIndex ...int) (*Shape, error) {
+	if g.slideIndex(slide) == -1 {
+		return nil, fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	shape.ShapeStyle = defaultStyle
+	if style != nil {
+		if err := validateShapeStyle(*style); err != nil {
+			return nil, err
+		}
+		shape.ShapeStyle = *style
+	}
+	shape.Style = slide.currentStyle
+	shape.ZIndex = slide.getNextZIndex(zIndex...)
+	slide.Shapes = append(slide.Shapes, &shape)
+	return &shape, nil
+}
+
+// validateShapeStyle comprueba los colores y el tipo de trazo del estilo de una forma
+func validateShapeStyle(style ShapeStyle) error {
+	if style.FillColor != "" && !isHexColor(style.FillColor) {
+		return fmt.Errorf("formato de color de relleno inválido %q: debe ser #RRGGBB", style.FillColor)
+	}
+	if style.StrokeColor != "" && !isHexColor(style.StrokeColor) {
+		return fmt.Errorf("formato de color de trazo inválido %q: debe ser #RRGGBB", style.StrokeColor)
+	}
+	if style.StrokeDash != StrokeSolid && strokeDashName(style.StrokeDash) == "" {
+		return fmt.Errorf("tipo de trazo no soportado: %q", style.StrokeDash)
+	}
+	return nil
+}
+
+// shapeStyleName genera el nombre del estilo gráfico de una forma
+func shapeStyleName(scope string, zIndex int) string {
+	return fmt.Sprintf("gr%s_%d", scope, zIndex)
+}
+
+// strokeDashName devuelve el nombre de la definición de trazo discontinuo en styles.xml
+func strokeDashName(dash string) string {
+	switch dash {
+	case StrokeDash:
+		return "goodpDash"
+	case StrokeDot:
+		return "goodpDot"
+	case StrokeDashDot:
+		return "goodpDashDot"
+	}
+	return ""
+}
+
+// hasShapes indica si alguna diapositiva contiene formas
+func (g *ODPGenerator) hasShapes() bool {
+	for _, slide := range g.Slides {
+		if len(slide.Shapes) > 0 {
+			return true
+		}
+	}
+	return false
+}
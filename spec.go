@@ -0,0 +1,382 @@
+package goodp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec es la descripción declarativa de una presentación, que FromSpec lee en JSON
+// o YAML y ToSpec escribe en JSON. Cada campo corresponde a una llamada del
+// generador: SetSlideSize o SetCustomSlideSize, SetBackgroundColor o
+// SetBackgroundImage, y por cada diapositiva AddSlide, AddTextBox o AddRichTextBox,
+// AddImage y SetNotes. Las medidas están en cm y las imágenes se indican con su ruta.
+type Spec struct {
+	AspectRatio string          `json:"aspectRatio,omitempty" yaml:"aspectRatio,omitempty"` // AspectRatio169 o AspectRatio43
+	SlideSize   *SlideSize      `json:"slideSize,omitempty" yaml:"slideSize,omitempty"`     // Tamaño personalizado; tiene preferencia sobre AspectRatio
+	Background  *BackgroundSpec `json:"background,omitempty" yaml:"background,omitempty"`   // Fondo de todas las diapositivas
+	Slides      []SlideSpec     `json:"slides" yaml:"slides"`
+}
+
+// BackgroundSpec es un fondo de color (#RRGGBB) o de imagen
+type BackgroundSpec struct {
+	Color string `json:"color,omitempty" yaml:"color,omitempty"`
+	Image string `json:"image,omitempty" yaml:"image,omitempty"` // Ruta de la imagen
+}
+
+// SlideSpec es una diapositiva. Title y Content crean los cuadros de título y
+// contenido de AddSlide; TextBoxes e Images se añaden después en ese orden.
+type SlideSpec struct {
+	ID         string          `json:"id,omitempty" yaml:"id,omitempty"`
+	Title      string          `json:"title,omitempty" yaml:"title,omitempty"`
+	Content    string          `json:"content,omitempty" yaml:"content,omitempty"`
+	Background *BackgroundSpec `json:"background,omitempty" yaml:"background,omitempty"`
+	TextBoxes  []TextBoxSpec   `json:"textBoxes,omitempty" yaml:"textBoxes,omitempty"`
+	Images     []ImageSpec     `json:"images,omitempty" yaml:"images,omitempty"`
+	Notes      string          `json:"notes,omitempty" yaml:"notes,omitempty"` // Notas del orador; cada línea es un párrafo
+}
+
+// TextBoxSpec es un cuadro de texto con texto sencillo (Content) o enriquecido
+// (Paragraphs). Sin Style se usa el estilo del contenido de AddSlide, y lo que no
+// indique Style también se toma de él.
+type TextBoxSpec struct {
+	Content    string          `json:"content,omitempty" yaml:"content,omitempty"`
+	Paragraphs []Paragraph     `json:"paragraphs,omitempty" yaml:"paragraphs,omitempty"`
+	X          float64         `json:"x" yaml:"x"`
+	Y          float64         `json:"y" yaml:"y"`
+	Width      float64         `json:"width" yaml:"width"`
+	Height     float64         `json:"height" yaml:"height"`
+	Style      *TextStyle      `json:"style,omitempty" yaml:"style,omitempty"`
+	Props      *TextProperties `json:"props,omitempty" yaml:"props,omitempty"`
+	ZIndex     int             `json:"zIndex,omitempty" yaml:"zIndex,omitempty"` // 0 para usar el siguiente libre
+}
+
+// ImageSpec es una imagen leída de un archivo
+type ImageSpec struct {
+	Path   string  `json:"path" yaml:"path"`
+	X      float64 `json:"x" yaml:"x"`
+	Y      float64 `json:"y" yaml:"y"`
+	Width  float64 `json:"width" yaml:"width"`
+	Height float64 `json:"height" yaml:"height"`
+	ZIndex int     `json:"zIndex,omitempty" yaml:"zIndex,omitempty"` // 0 para usar el siguiente libre
+}
+
+// specTextStyle es el estilo de los cuadros de texto sin estilo, el mismo que el del
+// contenido de AddSlide
+var specTextStyle = TextStyle{
+	FontSize:   "18pt",
+	FontFamily: "Liberation Sans",
+	Color:      "#000000",
+}
+
+// FromSpec construye una presentación a partir de su descripción en JSON o YAML.
+// Las rutas de las imágenes son relativas a files, que puede ser nil si la
+// descripción no tiene imágenes (por ejemplo os.DirFS con la carpeta del archivo).
+func FromSpec(r io.Reader, files fs.FS) (*ODPGenerator, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := decodeSpec(data)
+	if err != nil {
+		return nil, err
+	}
+	return spec.build(files)
+}
+
+// decodeSpec lee una descripción en JSON, si empieza por "{", o en YAML. Los campos
+// desconocidos son un error para detectar las erratas.
+func decodeSpec(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(spec); err != nil {
+			return nil, fmt.Errorf("descripción inválida: %v", err)
+		}
+		return spec, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(spec); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("descripción inválida: el documento está vacío")
+		}
+		return nil, fmt.Errorf("descripción inválida: %v", err)
+	}
+	return spec, nil
+}
+
+// build añade a una presentación nueva las diapositivas de la descripción
+func (spec *Spec) build(files fs.FS) (*ODPGenerator, error) {
+	readImage := func(name string) ([]byte, string, error) {
+		if files == nil {
+			return nil, "", fmt.Errorf("no se puede leer la imagen %q: no se indicó de dónde leer las imágenes", name)
+		}
+		data, err := fs.ReadFile(files, path.Clean(strings.TrimPrefix(name, "./")))
+		if err != nil {
+			return nil, "", fmt.Errorf("no se puede leer la imagen %q: %v", name, err)
+		}
+		return data, path.Ext(name), nil
+	}
+
+	g := New()
+	if spec.AspectRatio != "" {
+		if spec.AspectRatio != AspectRatio169 && spec.AspectRatio != AspectRatio43 {
+			return nil, fmt.Errorf("relación de aspecto no soportada: %s", spec.AspectRatio)
+		}
+		g.SetSlideSize(spec.AspectRatio)
+	}
+	if spec.SlideSize != nil {
+		if spec.SlideSize.Width <= 0 || spec.SlideSize.Height <= 0 {
+			return nil, fmt.Errorf("el tamaño de las diapositivas debe ser positivo")
+		}
+		g.SetCustomSlideSize(spec.SlideSize.Width, spec.SlideSize.Height)
+	}
+	if background := spec.Background; background != nil {
+		if background.Image != "" {
+			data, extension, err := readImage(background.Image)
+			if err != nil {
+				return nil, err
+			}
+			if err := g.SetBackgroundImage(data, extension); err != nil {
+				return nil, err
+			}
+		} else {
+			g.SetBackgroundColor(background.Color)
+		}
+	}
+
+	for i, s := range spec.Slides {
+		slide := g.AddSlide(s.Title, s.Content)
+		if s.ID != "" {
+			if err := g.SetSlideID(slide, s.ID); err != nil {
+				return nil, fmt.Errorf("diapositiva %d: %v", i+1, err)
+			}
+		}
+		if background := s.Background; background != nil {
+			var err error
+			if background.Image != "" {
+				var data []byte
+				var extension string
+				if data, extension, err = readImage(background.Image); err == nil {
+					err = g.SetSlideBackground(slide, data, extension)
+				}
+			} else {
+				err = g.SetSlideBackgroundColor(slide, background.Color)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("diapositiva %d: %v", i+1, err)
+			}
+		}
+
+		for _, tb := range s.TextBoxes {
+			slide.currentStyle = specTextStyle
+			if tb.Style != nil {
+				slide.currentStyle = inheritTextStyle(*tb.Style, specTextStyle)
+			}
+			var err error
+			if len(tb.Paragraphs) > 0 {
+				_, err = g.AddRichTextBox(slide, tb.Paragraphs, tb.X, tb.Y, tb.Width, tb.Height, tb.Props, specZIndex(tb.ZIndex)...)
+			} else {
+				_, err = g.AddTextBox(slide, tb.Content, tb.X, tb.Y, tb.Width, tb.Height, tb.Props, specZIndex(tb.ZIndex)...)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("diapositiva %d: %v", i+1, err)
+			}
+		}
+		for _, img := range s.Images {
+			data, extension, err := readImage(img.Path)
+			if err != nil {
+				return nil, fmt.Errorf("diapositiva %d: %v", i+1, err)
+			}
+			if _, err := g.AddImage(slide, data, extension, img.X, img.Y, img.Width, img.Height, specZIndex(img.ZIndex)...); err != nil {
+				return nil, fmt.Errorf("diapositiva %d: imagen %q: %v", i+1, img.Path, err)
+			}
+		}
+		g.SetNotes(slide, s.Notes)
+	}
+	return g, nil
+}
+
+// specZIndex devuelve el Z-index opcional de un elemento; 0 usa el siguiente libre
+func specZIndex(value int) []int {
+	if value == 0 {
+		return nil
+	}
+	return []int{value}
+}
+
+// ToSpec escribe la descripción en JSON de la presentación, de forma que FromSpec
+// con os.DirFS(dir) la vuelva a construir. Las imágenes se guardan en dir con su
+// nombre dentro del archivo .odp (por ejemplo "Pictures/slide0_image0.png"); con
+// dir vacío solo se escribe la descripción. Si el nombre de alguna imagen saldría de
+// dir, se devuelve un error sin escribir nada. Las listas, tablas, formas, gráficos,
+// animaciones y transiciones no forman parte de la descripción y se omiten.
+func (g *ODPGenerator) ToSpec(w io.Writer, dir string) error {
+	spec, images, err := g.spec()
+	if err != nil {
+		return err
+	}
+	if dir != "" {
+		for name, data := range images {
+			filename := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filename, data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(spec)
+}
+
+// spec devuelve la descripción de la presentación y las imágenes que usa por nombre
+func (g *ODPGenerator) spec() (*Spec, map[string][]byte, error) {
+	// Las imágenes se escriben fuera del paquete, así que sus nombres no pueden salir de dir
+	images := make(map[string][]byte)
+	addImage := func(name string, data []byte) (string, error) {
+		clean, err := cleanImagePath(name)
+		if err != nil {
+			return "", err
+		}
+		images[clean] = data
+		return clean, nil
+	}
+	background := func(b *Background) (*BackgroundSpec, error) {
+		switch {
+		case b == nil:
+			return nil, nil
+		case b.Type == BackgroundColor:
+			return &BackgroundSpec{Color: b.Color}, nil
+		}
+		name, err := addImage(b.Name, b.Data)
+		if err != nil {
+			return nil, err
+		}
+		return &BackgroundSpec{Image: name}, nil
+	}
+
+	size := g.SlideSize
+	spec := &Spec{
+		SlideSize: &size,
+		Slides:    make([]SlideSpec, 0, len(g.Slides)),
+	}
+	var err error
+	if spec.Background, err = background(g.Background); err != nil {
+		return nil, nil, fmt.Errorf("fondo: %v", err)
+	}
+	for i, slide := range g.Slides {
+		s := SlideSpec{
+			ID:    slide.ID,
+			Notes: paragraphsPlainText(slide.Notes),
+		}
+		if s.Background, err = background(slide.Background); err != nil {
+			return nil, nil, fmt.Errorf("diapositiva %d: fondo: %v", i+1, err)
+		}
+		for _, tb := range slide.TextBoxes {
+			x, y, width, height, err := parseFrame(tb.X, tb.Y, tb.Width, tb.Height)
+			if err != nil {
+				return nil, nil, fmt.Errorf("diapositiva %d: cuadro de texto %d: %v", i+1, tb.ZIndex, err)
+			}
+			style := tb.Style
+			box := TextBoxSpec{
+				Content: strings.Join(unescapeXML(tb.Content), "\n"),
+				X:       x,
+				Y:       y,
+				Width:   width,
+				Height:  height,
+				Style:   &style,
+				Props:   tb.Props,
+				ZIndex:  tb.ZIndex,
+			}
+			if len(tb.Paragraphs) > 0 {
+				box.Content = ""
+				box.Paragraphs = unescapeParagraphs(tb.Paragraphs)
+			}
+			s.TextBoxes = append(s.TextBoxes, box)
+		}
+		for _, img := range slide.Images {
+			x, y, width, height, err := parseFrame(img.X, img.Y, img.Width, img.Height)
+			if err != nil {
+				return nil, nil, fmt.Errorf("diapositiva %d: imagen %s: %v", i+1, img.Name, err)
+			}
+			name, err := addImage(img.Name, img.Data)
+			if err != nil {
+				return nil, nil, fmt.Errorf("diapositiva %d: %v", i+1, err)
+			}
+			s.Images = append(s.Images, ImageSpec{
+				Path:   name,
+				X:      x,
+				Y:      y,
+				Width:  width,
+				Height: height,
+				ZIndex: img.ZIndex,
+			})
+		}
+		spec.Slides = append(spec.Slides, s)
+	}
+	return spec, images, nil
+}
+
+// cleanImagePath limpia la ruta de una imagen dentro del paquete y comprueba que sea
+// relativa y no salga de la carpeta en la que se guardan las imágenes fuera de él
+func cleanImagePath(name string) (string, error) {
+	clean := path.Clean(name)
+	if path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("ruta de imagen no válida: %q", name)
+	}
+	return clean, nil
+}
+
+// parseFrame convierte a cm la posición y el tamaño de un elemento
+func parseFrame(values ...string) (x, y, width, height float64, err error) {
+	numbers := make([]float64, len(values))
+	for i, value := range values {
+		if numbers[i], err = parseLength(value); err != nil {
+			return 0, 0, 0, 0, err
+		}
+	}
+	return numbers[0], numbers[1], numbers[2], numbers[3], nil
+}
+
+// unescapeParagraphs copia unos párrafos deshaciendo el escapado del texto de los fragmentos
+func unescapeParagraphs(paragraphs []Paragraph) []Paragraph {
+	copied := make([]Paragraph, 0, len(paragraphs))
+	for _, p := range paragraphs {
+		runs := make([]Run, 0, len(p.Runs))
+		for _, run := range p.Runs {
+			run.Text = strings.Join(unescapeXML(run.Text), "\n")
+			runs = append(runs, run)
+		}
+		copied = append(copied, Paragraph{Runs: runs, Props: p.Props})
+	}
+	return copied
+}
+
+// paragraphsPlainText devuelve el texto de unos párrafos con un párrafo por línea
+func paragraphsPlainText(paragraphs []Paragraph) string {
+	lines := make([]string, 0, len(paragraphs))
+	for _, p := range paragraphs {
+		var text strings.Builder
+		for _, run := range p.Runs {
+			text.WriteString(strings.Join(unescapeXML(run.Text), "\n"))
+		}
+		lines = append(lines, text.String())
+	}
+	return strings.Join(lines, "\n")
+}
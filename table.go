@@ -0,0 +1,269 @@
+package goodp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CellStyle define el aspecto de una celda de tabla
+type CellStyle struct {
+	FillColor       string    // Color de relleno (#RRGGBB), vacío para sin relleno
+	BorderColor     string    // Color del borde (#RRGGBB), vacío para sin borde
+	BorderWidth     float64   // Grosor del borde en puntos
+	Text            TextStyle // Estilo del texto (los campos vacíos se heredan de la tabla)
+	HorizontalAlign string    // "left", "center", "right", "justify"
+	VerticalAlign   string    // "top", "middle", "bottom"
+}
+
+// TableCell es una celda de una tabla. Las celdas cubiertas por una celda combinada
+// tienen Covered a true y no se muestran.
+type TableCell struct {
+	Text    string
+	Style   *CellStyle
+	RowSpan int
+	ColSpan int
+	Covered bool
+}
+
+// CellMerge combina las celdas de un rango que empieza en la fila Row y la columna Col
+type CellMerge struct {
+	Row     int
+	Col     int
+	RowSpan int
+	ColSpan int
+}
+
+// TableOptions son las opciones de formato de AddTable
+type TableOptions struct {
+	ColumnWidths []float64  // Ancho de cada columna en cm (por defecto, todas iguales)
+	HeaderRow    bool       // La primera fila es una fila de encabezado
+	HeaderStyle  *CellStyle // Estilo de las celdas del encabezado
+	CellStyle    *CellStyle // Estilo por defecto del resto de celdas
+	Merges       []CellMerge
+}
+
+// Table es una tabla nativa de Impress, editable desde la aplicación
+type Table struct {
+	Rows         [][]TableCell
+	ColumnWidths []string // Ancho de cada columna en cm
+	RowHeight    string   // Alto de cada fila en cm
+	HeaderRow    bool
+	X            string // Posición X en cm
+	Y            string // Posición Y en cm
+	Width        string // Ancho en cm
+	Height       string // Alto en cm
+	Style        TextStyle
+	ZIndex       int
+}
+
+// Alineaciones válidas del texto de las celdas
+var (
+	cellHorizontalAligns = map[string]bool{"": true, "left": true, "center": true, "right": true, "justify": true, "start": true, "end": true}
+	cellVerticalAligns   = map[string]bool{"": true, "top": true, "middle": true, "bottom": true}
+)
+
+// Estilos por defecto de las tablas
+var (
+	defaultHeaderCellStyle = CellStyle{
+		FillColor:   "#4472C4",
+		BorderColor: "#FFFFFF",
+		BorderWidth: 1,
+		Text:        TextStyle{Color: "#FFFFFF", Bold: true},
+	}
+	defaultTableCellStyle = CellStyle{
+		FillColor:   "#E9EBF5",
+		BorderColor: "#FFFFFF",
+		BorderWidth: 1,
+	}
+)
+
+// AddTable añade una tabla a la diapositiva especificada y devuelve un puntero a ella para
+// poder cambiar el estilo de celdas concretas. El texto usa el estilo actual de la
+// diapositiva (SetTextStyle). Si opts es nil se usan las opciones por defecto.
+func (g *ODPGenerator) AddTable(slide *Slide, rows [][]string, x, y, width, height float64, opts *TableOptions, zIndex ...int) (*Table, error) {
+	if g.slideIndex(slide) == -1 {
+		return nil, fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	if opts == nil {
+		opts = &TableOptions{}
+	}
+
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if len(rows) == 0 || columns == 0 {
+		return nil, fmt.Errorf("la tabla no tiene celdas")
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("las dimensiones de la tabla deben ser positivas")
+	}
+
+	// Anchos de columna
+	widths := opts.ColumnWidths
+	if len(widths) == 0 {
+		widths = make([]float64, columns)
+		for i := range widths {
+			widths[i] = width / float64(columns)
+		}
+	}
+	if len(widths) != columns {
+		return nil, fmt.Errorf("se indicaron %d anchos de columna para una tabla de %d columnas", len(widths), columns)
+	}
+	columnWidths := make([]string, columns)
+	for i, w := range widths {
+		if w <= 0 {
+			return nil, fmt.Errorf("el ancho de la columna %d debe ser positivo", i)
+		}
+		columnWidths[i] = fmt.Sprintf("%.2fcm", w)
+	}
+
+	headerStyle := defaultHeaderCellStyle
+	if opts.HeaderStyle != nil {
+		if err := validateCellStyle(*opts.HeaderStyle); err != nil {
+			return nil, fmt.Errorf("estilo del encabezado: %v", err)
+		}
+		headerStyle = *opts.HeaderStyle
+	}
+	cellStyle := defaultTableCellStyle
+	if opts.CellStyle != nil {
+		if err := validateCellStyle(*opts.CellStyle); err != nil {
+			return nil, fmt.Errorf("estilo de las celdas: %v", err)
+		}
+		cellStyle = *opts.CellStyle
+	}
+
+	// Celdas (las filas cortas se completan con celdas vacías)
+	cells := make([][]TableCell, len(rows))
+	for r, row := range rows {
+		cells[r] = make([]TableCell, columns)
+		for c := range cells[r] {
+			style := cellStyle
+			if opts.HeaderRow && r == 0 {
+				style = headerStyle
+			}
+			text := ""
+			if c < len(row) {
+				text = row[c]
+			}
+			cells[r][c] = TableCell{
+				Text:    escapeXML(text),
+				Style:   &style,
+				RowSpan: 1,
+				ColSpan: 1,
+			}
+		}
+	}
+
+	table := &Table{
+		Rows:         cells,
+		ColumnWidths: columnWidths,
+		RowHeight:    fmt.Sprintf("%.2fcm", height/float64(len(rows))),
+		HeaderRow:    opts.HeaderRow,
+		X:            fmt.Sprintf("%.2fcm", x),
+		Y:            fmt.Sprintf("%.2fcm", y),
+		Width:        fmt.Sprintf("%.2fcm", width),
+		Height:       fmt.Sprintf("%.2fcm", height),
+		Style:        slide.currentStyle,
+	}
+	for _, merge := range opts.Merges {
+		if err := table.Merge(merge.Row, merge.Col, merge.RowSpan, merge.ColSpan); err != nil {
+			return nil, err
+		}
+	}
+
+	table.ZIndex = slide.getNextZIndex(zIndex...)
+	slide.Tables = append(slide.Tables, table)
+	return table, nil
+}
+
+// Cell devuelve la celda de la fila row y la columna col, o nil si no existe
+func (t *Table) Cell(row, col int) *TableCell {
+	if row < 0 || row >= len(t.Rows) || col < 0 || col >= len(t.Rows[row]) {
+		return nil
+	}
+	return &t.Rows[row][col]
+}
+
+// SetCellStyle cambia el estilo de una celda
+func (t *Table) SetCellStyle(row, col int, style CellStyle) error {
+	cell := t.Cell(row, col)
+	if cell == nil {
+		return fmt.Errorf("la celda (%d, %d) no existe", row, col)
+	}
+	if err := validateCellStyle(style); err != nil {
+		return err
+	}
+	cell.Style = &style
+	return nil
+}
+
+// validateCellStyle comprueba los colores y las alineaciones del estilo de una celda
+func validateCellStyle(style CellStyle) error {
+	if style.FillColor != "" && !isHexColor(style.FillColor) {
+		return fmt.Errorf("formato de color de relleno inválido %q: debe ser #RRGGBB", style.FillColor)
+	}
+	if style.BorderColor != "" && !isHexColor(style.BorderColor) {
+		return fmt.Errorf("formato de color de borde inválido %q: debe ser #RRGGBB", style.BorderColor)
+	}
+	if !cellHorizontalAligns[style.HorizontalAlign] {
+		return fmt.Errorf("alineación horizontal no soportada: %q", style.HorizontalAlign)
+	}
+	if !cellVerticalAligns[style.VerticalAlign] {
+		return fmt.Errorf("alineación vertical no soportada: %q", style.VerticalAlign)
+	}
+	return nil
+}
+
+// Merge combina rowSpan filas y colSpan columnas a partir de la celda (row, col).
+// El texto de las celdas cubiertas se descarta.
+func (t *Table) Merge(row, col, rowSpan, colSpan int) error {
+	if rowSpan < 1 || colSpan < 1 {
+		return fmt.Errorf("la combinación de celdas debe abarcar al menos una fila y una columna")
+	}
+	if t.Cell(row, col) == nil || t.Cell(row+rowSpan-1, col+colSpan-1) == nil {
+		return fmt.Errorf("la combinación de celdas (%d, %d) se sale de la tabla", row, col)
+	}
+
+	// Comprobar que el rango no se solapa con otra combinación
+	for r := row; r < row+rowSpan; r++ {
+		for c := col; c < col+colSpan; c++ {
+			cell := t.Rows[r][c]
+			if cell.Covered || cell.RowSpan > 1 || cell.ColSpan > 1 {
+				return fmt.Errorf("la combinación de celdas (%d, %d) se solapa con otra", row, col)
+			}
+		}
+	}
+
+	for r := row; r < row+rowSpan; r++ {
+		for c := col; c < col+colSpan; c++ {
+			if r != row || c != col {
+				t.Rows[r][c] = TableCell{Covered: true, RowSpan: 1, ColSpan: 1}
+			}
+		}
+	}
+	t.Rows[row][col].RowSpan = rowSpan
+	t.Rows[row][col].ColSpan = colSpan
+	return nil
+}
+
+// cellTextStyle devuelve el estilo del texto de una celda, heredando de la tabla
+// los campos que la celda no indica
+func cellTextStyle(table Table, cell TableCell) TextStyle {
+	if cell.Style == nil {
+		return table.Style
+	}
+	return mergeTextStyle(cell.Style.Text, table.Style)
+}
+
+// tableStyleName genera el nombre de un estilo automático de una tabla (columnas,
+// filas o celdas) a partir del ámbito, el Z-index de la tabla y los índices indicados
+func tableStyleName(prefix, scope string, zIndex int, indexes ...int) string {
+	parts := []string{prefix + scope, fmt.Sprint(zIndex)}
+	for _, index := range indexes {
+		parts = append(parts, fmt.Sprint(index))
+	}
+	return strings.Join(parts, "_")
+}
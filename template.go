@@ -0,0 +1,247 @@
+package goodp
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// documentTemplate guarda las partes de styles.xml de una plantilla que se copian
+// tal cual en las presentaciones generadas a partir de ella
+type documentTemplate struct {
+	namespaces  map[string]string // prefijo -> URI declarados en la raíz de styles.xml
+	parts       map[string]string // contenido XML de office:styles, office:master-styles...
+	masterPages []string          // nombres de las páginas maestras, en orden
+	files       []packageFile     // imágenes de la plantilla usadas por sus estilos
+}
+
+// Secciones de styles.xml que se conservan de la plantilla
+var templateSections = []string{"font-face-decls", "styles", "automatic-styles", "master-styles"}
+
+// NewFromTemplate crea una presentación que usa como plantilla un archivo .odp existente.
+// Se conservan las páginas maestras, los estilos y las imágenes de la plantilla, pero
+// no sus diapositivas. El tamaño de las diapositivas se toma de la primera página maestra.
+func NewFromTemplate(templateData []byte) (*ODPGenerator, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(templateData), int64(len(templateData)))
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir la plantilla: %w", err)
+	}
+
+	reader, err := newODPReader(zipReader)
+	if err != nil {
+		return nil, err
+	}
+	if reader.styles == nil {
+		return nil, fmt.Errorf("la plantilla no contiene styles.xml")
+	}
+
+	stylesData, err := reader.readFile("styles.xml")
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := parseDocumentTemplate(stylesData)
+	if err != nil {
+		return nil, fmt.Errorf("styles.xml inválido en la plantilla: %w", err)
+	}
+
+	// Nombres de las páginas maestras
+	if masters := reader.styles.child(nsOffice, "master-styles"); masters != nil {
+		for _, master := range masters.children(nsStyle, "master-page") {
+			tmpl.masterPages = append(tmpl.masterPages, master.attr(nsStyle, "name"))
+		}
+	}
+	if len(tmpl.masterPages) == 0 {
+		return nil, fmt.Errorf("la plantilla no define ninguna página maestra")
+	}
+
+	// Imágenes de la plantilla referenciadas desde sus estilos
+	mediaTypes := reader.manifestMediaTypes()
+	referenced := strings.Join([]string{tmpl.parts["styles"], tmpl.parts["automatic-styles"], tmpl.parts["master-styles"]}, "")
+	for _, f := range zipReader.File {
+		if !strings.HasPrefix(f.Name, "Pictures/") && !strings.HasPrefix(f.Name, "media/") {
+			continue
+		}
+		if !strings.Contains(referenced, f.Name) {
+			continue
+		}
+		data, err := reader.readFile(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		mediaType := mediaTypes[f.Name]
+		if mediaType == "" {
+			mediaType = "image/" + strings.TrimPrefix(filepath.Ext(f.Name), ".")
+		}
+		tmpl.files = append(tmpl.files, packageFile{Name: f.Name, MediaType: mediaType, Data: data})
+	}
+
+	g := New()
+	g.template = tmpl
+	if size, ok := reader.pageSize(reader.masterPage(tmpl.masterPages[0])); ok {
+		g.SlideSize = size
+	}
+
+	return g, nil
+}
+
+// parseDocumentTemplate extrae el XML original de las secciones de styles.xml que
+// se conservan, junto con los espacios de nombres declarados en la raíz
+func parseDocumentTemplate(data []byte) (*documentTemplate, error) {
+	tmpl := &documentTemplate{
+		namespaces: make(map[string]string),
+		parts:      make(map[string]string),
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	section := ""
+	sectionStart := int64(0)
+
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				for _, a := range t.Attr {
+					if a.Name.Space == "xmlns" {
+						tmpl.namespaces[a.Name.Local] = a.Value
+					}
+				}
+			}
+			if depth == 2 && t.Name.Space == "office" {
+				for _, name := range templateSections {
+					if t.Name.Local == name {
+						section = name
+						sectionStart = decoder.InputOffset()
+					}
+				}
+			}
+		case xml.EndElement:
+			if depth == 2 && section != "" {
+				// Las etiquetas vacías (<office:styles/>) no tienen contenido
+				if offset > sectionStart {
+					tmpl.parts[section] = string(data[sectionStart:offset])
+				} else {
+					tmpl.parts[section] = ""
+				}
+				section = ""
+			}
+			depth--
+		}
+	}
+
+	return tmpl, nil
+}
+
+// manifestMediaTypes devuelve los tipos MIME declarados en el manifiesto del paquete
+func (r *odpReader) manifestMediaTypes() map[string]string {
+	mediaTypes := make(map[string]string)
+	data, err := r.readFile("META-INF/manifest.xml")
+	if err != nil {
+		return mediaTypes
+	}
+	manifest, err := parseXMLTree(data)
+	if err != nil {
+		return mediaTypes
+	}
+	const nsManifest = "urn:oasis:names:tc:opendocument:xmlns:manifest:1.0"
+	for _, entry := range manifest.children(nsManifest, "file-entry") {
+		mediaTypes[entry.attr(nsManifest, "full-path")] = entry.attr(nsManifest, "media-type")
+	}
+	return mediaTypes
+}
+
+// MasterPageNames devuelve los nombres de las páginas maestras disponibles
+func (g *ODPGenerator) MasterPageNames() []string {
+	names := []string{"Default"}
+	if g.template != nil {
+		names = append([]string(nil), g.template.masterPages...)
+	}
+	for _, master := range g.MasterPages {
+		if master.Name != "Default" || g.template != nil {
+			names = append(names, master.Name)
+		}
+	}
+	return names
+}
+
+// hasMasterPage indica si existe una página maestra con el nombre indicado
+func (g *ODPGenerator) hasMasterPage(name string) bool {
+	for _, master := range g.MasterPageNames() {
+		if master == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDefaultMasterPage establece la página maestra que usarán las diapositivas
+// que se añadan a partir de ahora con AddSlide y AddBlankSlide
+func (g *ODPGenerator) SetDefaultMasterPage(name string) error {
+	if !g.hasMasterPage(name) {
+		return fmt.Errorf("la página maestra %q no existe", name)
+	}
+	g.masterPage = name
+	return nil
+}
+
+// masterPageName devuelve la página maestra de una diapositiva
+func (g *ODPGenerator) masterPageName(slide Slide) string {
+	if slide.MasterPage != "" {
+		return slide.MasterPage
+	}
+	return g.MasterPageNames()[0]
+}
+
+// templateNamespaces devuelve las declaraciones de espacios de nombres de la plantilla
+// que no están ya declaradas en la raíz de styles.xml
+func (g *ODPGenerator) templateNamespaces(declared ...string) string {
+	if g.template == nil {
+		return ""
+	}
+	skip := make(map[string]bool)
+	for _, prefix := range declared {
+		skip[prefix] = true
+	}
+
+	var sb strings.Builder
+	for _, prefix := range sortedKeys(g.template.namespaces) {
+		if skip[prefix] {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n                       xmlns:%s=\"%s\"", prefix, g.template.namespaces[prefix])
+	}
+	return sb.String()
+}
+
+// templatePart devuelve el XML original de una sección de styles.xml de la plantilla
+func (g *ODPGenerator) templatePart(section string) string {
+	if g.template == nil {
+		return ""
+	}
+	return g.template.parts[section]
+}
+
+// sortedKeys devuelve las claves de un mapa en orden alfabético
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,349 @@
+package goodp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Medidas usadas para colocar el texto de los cuadros de texto al exportar a PDF o a imagen
+const (
+	pointsPerCm     = 72 / 2.54
+	defaultFontSize = 18.0  // Tamaño en puntos del texto sin tamaño
+	textPaddingX    = 0.25  // Margen interior horizontal de los cuadros de texto, en cm
+	textPaddingY    = 0.125 // Margen interior vertical de los cuadros de texto, en cm
+	lineSpacing     = 1.17  // Alto de línea respecto al tamaño de la fuente
+	fontAscent      = 0.905 // Distancia de la parte superior de la línea a la línea base
+)
+
+// standardFont es una de las fuentes estándar de PDF, con sus anchos de carácter
+// (en milésimas del tamaño) para los caracteres ASCII imprimibles
+type standardFont struct {
+	Name   string
+	widths *[95]int // nil para las fuentes de ancho fijo (600)
+}
+
+// Anchos de carácter de las fuentes estándar (de sus métricas AFM). Las variantes
+// cursivas usan los anchos de la variante recta.
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556,
+		278, 278, 584, 584, 584, 556, 1015,
+		667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+		278, 278, 278, 469, 556, 333,
+		556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500,
+		334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556,
+		333, 333, 584, 584, 584, 611, 975,
+		722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+		333, 278, 333, 584, 556, 333,
+		556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611, 611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500,
+		389, 280, 389, 584,
+	}
+	timesWidths = [95]int{
+		250, 333, 408, 500, 500, 833, 778, 180, 333, 333, 500, 564, 250, 333, 250, 278,
+		500, 500, 500, 500, 500, 500, 500, 500, 500, 500,
+		278, 278, 564, 564, 564, 444, 921,
+		722, 667, 667, 722, 611, 556, 722, 722, 333, 389, 722, 611, 889, 722, 722, 556, 722, 667, 556, 611, 722, 722, 944, 722, 722, 611,
+		333, 278, 333, 469, 500, 333,
+		444, 500, 444, 500, 444, 333, 500, 500, 278, 278, 500, 278, 778, 500, 500, 500, 500, 333, 389, 278, 500, 500, 722, 500, 500, 444,
+		480, 200, 480, 541,
+	}
+	timesBoldWidths = [95]int{
+		250, 333, 555, 500, 500, 1000, 833, 278, 333, 333, 500, 570, 250, 333, 250, 278,
+		500, 500, 500, 500, 500, 500, 500, 500, 500, 500,
+		333, 333, 570, 570, 570, 500, 930,
+		722, 667, 722, 722, 667, 611, 778, 778, 389, 500, 778, 667, 944, 722, 778, 611, 778, 722, 556, 667, 722, 722, 1000, 722, 722, 667,
+		333, 278, 333, 581, 500, 333,
+		500, 556, 444, 556, 444, 333, 500, 556, 278, 333, 556, 278, 833, 556, 500, 556, 556, 444, 389, 333, 556, 500, 722, 500, 500, 444,
+		394, 220, 394, 520,
+	}
+)
+
+// Letras sin acento de las letras acentuadas de Latin-1, que tienen el mismo ancho
+var accentedLetters = strings.NewReplacer(
+	"À", "A", "Á", "A", "Â", "A", "Ã", "A", "Ä", "A", "Å", "A", "Ç", "C",
+	"È", "E", "É", "E", "Ê", "E", "Ë", "E", "Ì", "I", "Í", "I", "Î", "I", "Ï", "I",
+	"Ñ", "N", "Ò", "O", "Ó", "O", "Ô", "O", "Õ", "O", "Ö", "O", "Ù", "U", "Ú", "U", "Û", "U", "Ü", "U", "Ý", "Y",
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "ç", "c",
+	"è", "e", "é", "e", "ê", "e", "ë", "e", "ì", "i", "í", "i", "î", "i", "ï", "i",
+	"ñ", "n", "ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ù", "u", "ú", "u", "û", "u", "ü", "u", "ý", "y", "ÿ", "y",
+	"¡", "!", "¿", "?", "«", "<", "»", ">", "‘", "'", "’", "'", "“", "\"", "”", "\"", "–", "-", "\t", " ",
+)
+
+// fontFor elige la fuente estándar más parecida a la de un estilo de texto
+func fontFor(style TextStyle) standardFont {
+	family := strings.ToLower(style.FontFamily)
+	variant := 0
+	if style.Bold {
+		variant++
+	}
+	if style.Italic {
+		variant += 2
+	}
+
+	switch {
+	case strings.Contains(family, "mono") || strings.Contains(family, "courier") || strings.Contains(family, "consolas"):
+		return standardFont{Name: [4]string{"Courier", "Courier-Bold", "Courier-Oblique", "Courier-BoldOblique"}[variant]}
+	case !strings.Contains(family, "sans") && (strings.Contains(family, "serif") || strings.Contains(family, "times") ||
+		strings.Contains(family, "georgia") || strings.Contains(family, "garamond") || strings.Contains(family, "cambria")):
+		widths := &timesWidths
+		if style.Bold {
+			widths = &timesBoldWidths
+		}
+		return standardFont{Name: [4]string{"Times-Roman", "Times-Bold", "Times-Italic", "Times-BoldItalic"}[variant], widths: widths}
+	}
+	widths := &helveticaWidths
+	if style.Bold {
+		widths = &helveticaBoldWidths
+	}
+	return standardFont{Name: [4]string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique", "Helvetica-BoldOblique"}[variant], widths: widths}
+}
+
+// width devuelve el ancho en puntos de un texto con la fuente y el tamaño indicados
+func (f standardFont) width(text string, size float64) float64 {
+	if f.widths == nil {
+		return float64(len([]rune(text))) * 600 * size / 1000
+	}
+	total := 0
+	for _, c := range accentedLetters.Replace(text) {
+		switch {
+		case c >= 32 && c <= 126:
+			total += f.widths[c-32]
+		case c == '—' || c == '…':
+			total += 1000
+		default:
+			total += f.widths['o'-32]
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// fontSizePoints devuelve el tamaño en puntos de un estilo de texto
+func fontSizePoints(style TextStyle) float64 {
+	// Sin unidad el tamaño está en puntos
+	if size, err := strconv.ParseFloat(strings.TrimSpace(style.FontSize), 64); err == nil && size > 0 {
+		return size
+	}
+	size, err := parseLength(style.FontSize)
+	if err != nil || size <= 0 {
+		return defaultFontSize
+	}
+	return size * pointsPerCm
+}
+
+// unescapeXML deshace escapeXML y devuelve las líneas del texto
+func unescapeXML(text string) []string {
+	lines := strings.Split(text, "<text:line-break/>")
+	unescape := strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+	for i, line := range lines {
+		lines[i] = unescape.Replace(line)
+	}
+	return lines
+}
+
+// textFragment es un trozo de una línea de texto con un único estilo
+type textFragment struct {
+	Text     string // Texto sin escapar
+	Style    TextStyle
+	Link     string
+	X, Width float64 // Posición desde el borde izquierdo del cuadro y ancho, en puntos
+}
+
+// textLine es una línea de texto de un cuadro de texto ya colocada
+type textLine struct {
+	Fragments   []textFragment
+	Baseline    float64 // Línea base desde el borde superior del cuadro, en puntos
+	Size        float64 // Tamaño de la fuente más grande de la línea
+	WordSpacing float64 // Espacio añadido a cada espacio para justificar la línea
+}
+
+// textToken es una palabra, un grupo de espacios o un salto de línea de un párrafo
+type textToken struct {
+	Text  string
+	Style TextStyle
+	Link  string
+	Space bool
+	Break bool
+	Width float64
+}
+
+// textBoxParagraphs devuelve los párrafos de un cuadro de texto, convirtiendo el
+// texto simple en un párrafo con el estilo del cuadro
+func textBoxParagraphs(tb TextBox) []Paragraph {
+	if len(tb.Paragraphs) > 0 {
+		return tb.Paragraphs
+	}
+	return []Paragraph{{Runs: []Run{{Text: tb.Content, Style: tb.Style}}}}
+}
+
+// layoutTextBox divide en líneas el texto de un cuadro de texto de width x height
+// puntos, aplicando los márgenes interiores, la alineación y las sangrías
+func layoutTextBox(tb TextBox, width, height float64) []textLine {
+	boxProps := tb.Props
+	if boxProps == nil {
+		boxProps = NewDefaultTextProperties()
+	}
+	padX, padY := textPaddingX*pointsPerCm, textPaddingY*pointsPerCm
+	inner := width - 2*padX
+
+	var lines []textLine
+	top := 0.0
+	for _, paragraph := range textBoxParagraphs(tb) {
+		props := paragraph.Props
+		if props == nil {
+			props = boxProps
+		}
+		left := props.LeftIndent * pointsPerCm
+		right := props.RightIndent * pointsPerCm
+		indent := props.FirstLineIndent * pointsPerCm
+
+		emptySize := fontSizePoints(tb.Style)
+		var tokens []textToken
+		for _, run := range paragraph.Runs {
+			font := fontFor(run.Style)
+			size := fontSizePoints(run.Style)
+			emptySize = size
+			for i, line := range unescapeXML(run.Text) {
+				if i > 0 {
+					tokens = append(tokens, textToken{Break: true, Style: run.Style})
+				}
+				for _, word := range splitWords(line) {
+					tokens = append(tokens, textToken{
+						Text:  word,
+						Style: run.Style,
+						Link:  run.Link,
+						Space: strings.TrimSpace(word) == "",
+						Width: font.width(word, size),
+					})
+				}
+			}
+		}
+
+		var current []textToken
+		first := true
+		flush := func(last bool) {
+			// Los espacios del final no ocupan sitio
+			end := len(current)
+			for end > 0 && current[end-1].Space {
+				end--
+			}
+			used := current[:end]
+			start := left
+			if first {
+				start += indent
+			}
+			available := inner - start - right
+
+			line := textLine{Size: emptySize}
+			if len(used) > 0 {
+				line.Size = 0
+			}
+			lineWidth, spaces := 0.0, 0
+			for _, token := range used {
+				lineWidth += token.Width
+				if token.Space {
+					spaces += len(token.Text)
+				}
+				if size := fontSizePoints(token.Style); size > line.Size {
+					line.Size = size
+				}
+			}
+
+			x := start
+			switch props.HorizontalAlign {
+			case "center":
+				x += (available - lineWidth) / 2
+			case "right", "end":
+				x += available - lineWidth
+			case "justify":
+				if !last && spaces > 0 && available > lineWidth {
+					line.WordSpacing = (available - lineWidth) / float64(spaces)
+				}
+			}
+			for _, token := range used {
+				tokenWidth := token.Width
+				if token.Space {
+					tokenWidth += line.WordSpacing * float64(len(token.Text))
+				}
+				n := len(line.Fragments)
+				if n > 0 && line.Fragments[n-1].Link == token.Link && generateStyleName(line.Fragments[n-1].Style) == generateStyleName(token.Style) {
+					line.Fragments[n-1].Text += token.Text
+					line.Fragments[n-1].Width += tokenWidth
+				} else {
+					line.Fragments = append(line.Fragments, textFragment{Text: token.Text, Style: token.Style, Link: token.Link, X: x, Width: tokenWidth})
+				}
+				x += tokenWidth
+			}
+
+			line.Baseline = top + line.Size*fontAscent
+			top += line.Size * lineSpacing
+			lines = append(lines, line)
+			current = nil
+			first = false
+		}
+
+		lineWidth := 0.0
+		for _, token := range tokens {
+			if token.Break {
+				flush(true)
+				lineWidth = 0
+				continue
+			}
+			available := inner - left - right
+			if first {
+				available -= indent
+			}
+			if !token.Space && len(current) > 0 && lineWidth+token.Width > available {
+				flush(false)
+				lineWidth = 0
+			}
+			if token.Space && len(current) == 0 && !first {
+				// Los espacios al principio de una línea partida se omiten
+				continue
+			}
+			current = append(current, token)
+			lineWidth += token.Width
+		}
+		flush(true)
+	}
+
+	// Alineación vertical dentro del cuadro
+	offset := padY
+	switch boxProps.VerticalAlign {
+	case "middle", "center":
+		offset += (height - 2*padY - top) / 2
+	case "bottom":
+		offset += height - 2*padY - top
+	}
+	for i := range lines {
+		lines[i].Baseline += offset
+		for j := range lines[i].Fragments {
+			lines[i].Fragments[j].X += padX
+		}
+	}
+	return lines
+}
+
+// splitWords divide una línea en palabras y grupos de espacios
+func splitWords(line string) []string {
+	var words []string
+	start := 0
+	for i, c := range line {
+		if i == start {
+			continue
+		}
+		prev := line[i-1] == ' ' || line[i-1] == '\t'
+		if (c == ' ' || c == '\t') != prev {
+			words = append(words, line[start:i])
+			start = i
+		}
+	}
+	if start < len(line) {
+		words = append(words, line[start:])
+	}
+	return words
+}
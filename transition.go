@@ -0,0 +1,184 @@
+package goodp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TransitionType indica el efecto de transición al pasar a una diapositiva
+type TransitionType string
+
+const (
+	TransitionNone     TransitionType = "none"
+	TransitionFade     TransitionType = "fade"
+	TransitionDissolve TransitionType = "dissolve"
+	TransitionWipe     TransitionType = "wipe"
+	TransitionPush     TransitionType = "push"
+	TransitionCover    TransitionType = "cover"
+	TransitionUncover  TransitionType = "uncover"
+	TransitionSplit    TransitionType = "split"
+	TransitionCircle   TransitionType = "circle"
+)
+
+// TransitionDirection indica desde dónde entra la nueva diapositiva
+type TransitionDirection string
+
+const (
+	FromLeft   TransitionDirection = "left"
+	FromRight  TransitionDirection = "right"
+	FromTop    TransitionDirection = "top"
+	FromBottom TransitionDirection = "bottom"
+)
+
+// Velocidades de las transiciones
+const (
+	SpeedSlow   = "slow"
+	SpeedMedium = "medium"
+	SpeedFast   = "fast"
+)
+
+// Transition describe la transición con la que se muestra una diapositiva
+type Transition struct {
+	Type           TransitionType
+	Direction      TransitionDirection // Por defecto FromLeft (solo en los efectos con dirección)
+	Speed          string              // SpeedSlow, SpeedMedium (por defecto) o SpeedFast
+	AutoAdvance    float64             // Segundos hasta pasar a la siguiente diapositiva (0 = al hacer clic)
+	Sound          []byte              // Sonido que se reproduce durante la transición (opcional)
+	SoundExtension string              // Extensión del sonido, con el punto (".wav", ".mp3", ".ogg")
+	SoundName      string              // Ruta del sonido dentro del paquete (la asigna goodp)
+}
+
+// Tipos MIME de los formatos de sonido soportados
+var soundMediaTypes = map[string]string{
+	".wav": "audio/wav",
+	".mp3": "audio/mpeg",
+	".ogg": "audio/ogg",
+}
+
+// SetDefaultTransition establece la transición de todas las diapositivas que no tienen una propia
+func (g *ODPGenerator) SetDefaultTransition(transition Transition) error {
+	prepared, err := prepareTransition(transition, "media/transition")
+	if err != nil {
+		return err
+	}
+	g.Transition = prepared
+	return nil
+}
+
+// SetSlideTransition establece la transición de una diapositiva. Con TransitionNone
+// la diapositiva no usa la transición por defecto de la presentación.
+func (g *ODPGenerator) SetSlideTransition(slide *Slide, transition Transition) error {
+	slideIndex := g.slideIndex(slide)
+	if slideIndex == -1 {
+		return fmt.Errorf("la diapositiva especificada no pertenece a esta presentación")
+	}
+	prepared, err := prepareTransition(transition, fmt.Sprintf("media/slide%d_transition", slideIndex))
+	if err != nil {
+		return err
+	}
+	slide.Transition = prepared
+	return nil
+}
+
+// prepareTransition valida la transición y completa los valores por defecto
+func prepareTransition(transition Transition, soundPrefix string) (*Transition, error) {
+	if _, _, _, ok := transitionSMIL(transition); !ok && transition.Type != TransitionNone {
+		return nil, fmt.Errorf("tipo de transición no soportado: %q", transition.Type)
+	}
+	switch transition.Direction {
+	case "":
+		transition.Direction = FromLeft
+	case FromLeft, FromRight, FromTop, FromBottom:
+	default:
+		return nil, fmt.Errorf("dirección de transición no soportada: %q", transition.Direction)
+	}
+	switch transition.Speed {
+	case "":
+		transition.Speed = SpeedMedium
+	case SpeedSlow, SpeedMedium, SpeedFast:
+	default:
+		return nil, fmt.Errorf("velocidad de transición no soportada: %q", transition.Speed)
+	}
+	if transition.AutoAdvance < 0 {
+		return nil, fmt.Errorf("el tiempo de avance automático no puede ser negativo")
+	}
+
+	transition.SoundName = ""
+	if len(transition.Sound) > 0 {
+		extension := strings.ToLower(transition.SoundExtension)
+		if !strings.HasPrefix(extension, ".") {
+			extension = "." + extension
+		}
+		if _, ok := soundMediaTypes[extension]; !ok {
+			return nil, fmt.Errorf("formato de sonido no soportado: %s", transition.SoundExtension)
+		}
+		transition.SoundExtension = extension
+		transition.SoundName = soundPrefix + extension
+	}
+	return &transition, nil
+}
+
+// transitionSMIL devuelve el tipo y subtipo SMIL de una transición y si el efecto
+// se reproduce en sentido inverso
+func transitionSMIL(t Transition) (smilType, subtype string, reverse, ok bool) {
+	horizontal := t.Direction == FromLeft || t.Direction == FromRight || t.Direction == ""
+	reversed := t.Direction == FromRight || t.Direction == FromBottom
+	fromSubtype := map[TransitionDirection]string{
+		"": "fromLeft", FromLeft: "fromLeft", FromRight: "fromRight", FromTop: "fromTop", FromBottom: "fromBottom",
+	}[t.Direction]
+
+	switch t.Type {
+	case TransitionFade:
+		return "fade", "crossfade", false, true
+	case TransitionDissolve:
+		return "dissolve", "", false, true
+	case TransitionWipe:
+		if horizontal {
+			return "barWipe", "leftToRight", reversed, true
+		}
+		return "barWipe", "topToBottom", reversed, true
+	case TransitionPush:
+		return "pushWipe", fromSubtype, false, true
+	case TransitionCover:
+		return "slideWipe", fromSubtype, false, true
+	case TransitionUncover:
+		return "slideWipe", fromSubtype, true, true
+	case TransitionSplit:
+		if horizontal {
+			return "barnDoorWipe", "vertical", false, true
+		}
+		return "barnDoorWipe", "horizontal", false, true
+	case TransitionCircle:
+		return "ellipseWipe", "circle", false, true
+	}
+	return "", "", false, false
+}
+
+// slideTransition devuelve la transición que usa una diapositiva, o nil si no tiene
+func (g *ODPGenerator) slideTransition(slide Slide) *Transition {
+	transition := slide.Transition
+	if transition == nil {
+		transition = g.Transition
+	}
+	if transition == nil || transition.Type == TransitionNone {
+		return nil
+	}
+	return transition
+}
+
+// transitionAttributes genera los atributos de transición de un estilo de página
+func transitionAttributes(t *Transition) string {
+	smilType, subtype, reverse, _ := transitionSMIL(*t)
+	attributes := []string{fmt.Sprintf(`presentation:transition-speed="%s"`, t.Speed), fmt.Sprintf(`smil:type="%s"`, smilType)}
+	if subtype != "" {
+		attributes = append(attributes, fmt.Sprintf(`smil:subtype="%s"`, subtype))
+	}
+	if reverse {
+		attributes = append(attributes, `smil:direction="reverse"`)
+	}
+	if t.AutoAdvance > 0 {
+		attributes = append(attributes, `presentation:transition-type="automatic"`,
+			fmt.Sprintf(`presentation:duration="PT%gS"`, t.AutoAdvance))
+	}
+	return strings.Join(attributes, " ")
+}